@@ -114,11 +114,16 @@ const server = http.createServer(async (req, res) => {
 
   try {
     // Handle comments FIRST (must come before generic subreddit match)
-    // Pattern: /api/r/:subreddit/comments/:id
-    const commentsMatch = pathname.match(/^\/api(\/r\/[^/]+\/comments\/[^/]+\/)/)
+    // Pattern: /api/r/:subreddit/comments/:id or /api/comments/:id
+    const commentsMatch = pathname.match(/^\/api((?:\/r\/[^/]+)?\/comments\/[^/]+\/)/)
     if (commentsMatch) {
       const path = commentsMatch[1]
-      const redditUrl = `https://www.reddit.com${path}.json`
+      // Forward the comment sort, and the comment limit used by exports
+      const query = new URLSearchParams()
+      if (parsedUrl.query.sort) query.set('sort', String(parsedUrl.query.sort))
+      if (parsedUrl.query.limit) query.set('limit', String(parsedUrl.query.limit))
+      const search = query.toString() ? `?${query.toString()}` : ''
+      const redditUrl = `https://www.reddit.com${path}.json${search}`
       const cacheKey = redditUrl
 
       // Check cache
@@ -261,6 +266,7 @@ server.listen(API_PORT, () => {
 │  GET /api/r/:subreddit                           │
 │  GET /api/r/:subreddit/:sort (hot/new/top...)    │
 │  GET /api/r/:subreddit/comments/:id              │
+│  GET /api/comments/:id                           │
 │  GET /api/search.json?q=:query                   │
 │  GET /api/config                                 │
 │  GET /health                                     │
@@ -137,11 +137,16 @@ const server = http.createServer(async (req, res) => {
       return
     }
 
-    // Handle comments: /api/r/:subreddit/comments/:id
-    const commentsMatch = pathname.match(/^\/api(\/r\/[^/]+\/comments\/[^/]+\/)/)
+    // Handle comments: /api/r/:subreddit/comments/:id or /api/comments/:id
+    const commentsMatch = pathname.match(/^\/api((?:\/r\/[^/]+)?\/comments\/[^/]+\/)/)
     if (commentsMatch) {
       const path = commentsMatch[1]
-      const redditUrl = `https://www.reddit.com${path}.json`
+      // Forward the comment sort, and the comment limit used by exports
+      const query = new URLSearchParams()
+      if (parsedUrl.query.sort) query.set('sort', String(parsedUrl.query.sort))
+      if (parsedUrl.query.limit) query.set('limit', String(parsedUrl.query.limit))
+      const search = query.toString() ? `?${query.toString()}` : ''
+      const redditUrl = `https://www.reddit.com${path}.json${search}`
       const cacheKey = redditUrl
 
       // Check cache
@@ -222,6 +227,7 @@ server.listen(API_PORT, () => {
 │  Endpoints:                         │
 │  GET /api/r/:subreddit              │
 │  GET /api/r/:subreddit/comments/:id │
+│  GET /api/comments/:id              │
 │  GET /api/search.json?q=:query      │
 │  GET /health                        │
 │  GET /api/stats                     │
@@ -0,0 +1,584 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============= Authentication =============
+
+// Logging in uses Reddit's OAuth flows for personal apps. An "installed" app
+// sends the user to Reddit to approve access and receives the answer on a
+// listener on localhost; a "script" app trades the account's own username
+// and password for a token. Once logged in, the client talks to
+// oauth.reddit.com directly instead of going through the proxy.
+
+// errNotLoggedIn is returned by requests that need an account.
+var errNotLoggedIn = errors.New("not logged in (run redditview login)")
+
+const (
+	authFlowInstalled = "installed"
+	authFlowScript    = "script"
+
+	// loginTimeout bounds how long login waits for the browser redirect.
+	loginTimeout = 5 * time.Minute
+	// tokenRefreshMargin renews tokens this long before they expire.
+	tokenRefreshMargin = time.Minute
+)
+
+// oauthToken is what is kept in the token file between runs.
+type oauthToken struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type"`
+	Scope        string    `json:"scope"`
+	Expiry       time.Time `json:"expiry"`
+	Username     string    `json:"username,omitempty"`
+}
+
+func (t oauthToken) expired() bool {
+	return t.AccessToken == "" || !clock.Now().Add(tokenRefreshMargin).Before(t.Expiry)
+}
+
+// tokenPath is the token file: auth.token_file from the config, or
+// redditview/token.json in the user's config directory.
+func tokenPath() string {
+	if appConfig.Auth.TokenFile != "" {
+		return appConfig.Auth.TokenFile
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "redditview", "token.json")
+}
+
+func loadToken(path string) (oauthToken, error) {
+	var tok oauthToken
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return tok, err
+	}
+	if err := json.Unmarshal(data, &tok); err != nil {
+		return tok, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return tok, nil
+}
+
+// saveToken writes the token readable by the current user only.
+func saveToken(path string, tok oauthToken) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(tok, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ============= Token Requests =============
+
+// tokenResponse is Reddit's access_token reply. Failures may come back as
+// 200 with only "error" set.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	Scope        string `json:"scope"`
+	Error        string `json:"error"`
+}
+
+func authHTTPClient() *http.Client {
+	return &http.Client{Timeout: time.Duration(appConfig.API.TimeoutSeconds) * time.Second}
+}
+
+// requestToken posts a grant to the token endpoint.
+func requestToken(form url.Values) (oauthToken, error) {
+	cfg := appConfig.Auth
+	req, err := http.NewRequest(http.MethodPost, cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return oauthToken{}, err
+	}
+	req.SetBasicAuth(cfg.ClientID, cfg.ClientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", cfg.UserAgent)
+
+	resp, err := authHTTPClient().Do(req)
+	if err != nil {
+		return oauthToken{}, err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+
+	var body tokenResponse
+	if err := json.Unmarshal(data, &body); err != nil {
+		return oauthToken{}, fmt.Errorf("token request: %s", resp.Status)
+	}
+	if body.Error != "" {
+		return oauthToken{}, fmt.Errorf("token request: %s", body.Error)
+	}
+	if resp.StatusCode != http.StatusOK || body.AccessToken == "" {
+		return oauthToken{}, fmt.Errorf("token request: %s", resp.Status)
+	}
+	return oauthToken{
+		AccessToken:  body.AccessToken,
+		RefreshToken: body.RefreshToken,
+		TokenType:    body.TokenType,
+		Scope:        body.Scope,
+		Expiry:       clock.Now().Add(time.Duration(body.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// passwordGrant logs a script app in with the account's credentials. The
+// password may come from REDDITVIEW_PASSWORD instead of the config file.
+func passwordGrant() (oauthToken, error) {
+	cfg := appConfig.Auth
+	password := cfg.Password
+	if password == "" {
+		password = os.Getenv("REDDITVIEW_PASSWORD")
+	}
+	if cfg.Username == "" || password == "" {
+		return oauthToken{}, fmt.Errorf("script login needs auth.username and a password")
+	}
+	return requestToken(url.Values{
+		"grant_type": {"password"},
+		"username":   {cfg.Username},
+		"password":   {password},
+		"scope":      {strings.Join(cfg.Scopes, " ")},
+	})
+}
+
+// ============= Session =============
+
+// session holds the logged-in token, renewing and saving it as it expires.
+// It is shared by every request, so access is locked.
+type session struct {
+	mu    sync.Mutex
+	token oauthToken
+	path  string
+}
+
+// loadSession returns the saved session, or nil when auth is not configured
+// or nobody has logged in.
+func loadSession() (*session, error) {
+	if appConfig.Auth.ClientID == "" {
+		return nil, nil
+	}
+	path := tokenPath()
+	tok, err := loadToken(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &session{token: tok, path: path}, nil
+}
+
+func (s *session) username() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token.Username
+}
+
+// accessToken returns a current access token, renewing it first when it is
+// about to expire or force is set.
+func (s *session) accessToken(force bool) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !force && !s.token.expired() {
+		return s.token.AccessToken, nil
+	}
+
+	var tok oauthToken
+	var err error
+	switch {
+	case s.token.RefreshToken != "":
+		tok, err = requestToken(url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {s.token.RefreshToken},
+		})
+		tok.RefreshToken = s.token.RefreshToken // not repeated in the reply
+	case appConfig.Auth.Flow == authFlowScript:
+		tok, err = passwordGrant()
+	default:
+		return "", fmt.Errorf("session expired; run redditview login")
+	}
+	if err != nil {
+		return "", err
+	}
+	tok.Username = s.token.Username
+	s.token = tok
+	if err := saveToken(s.path, tok); err != nil {
+		return "", fmt.Errorf("could not save token: %w", err)
+	}
+	return tok.AccessToken, nil
+}
+
+// authTransport signs requests with the session's token, renewing it once
+// and retrying when Reddit answers 401.
+type authTransport struct {
+	base    http.RoundTripper
+	session *session
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.send(req, false)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil // body already consumed
+	}
+	resp.Body.Close()
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+	return t.send(req, true)
+}
+
+func (t *authTransport) send(req *http.Request, force bool) (*http.Response, error) {
+	tok, err := t.session.accessToken(force)
+	if err != nil {
+		return nil, err
+	}
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "bearer "+tok)
+	r.Header.Set("User-Agent", appConfig.Auth.UserAgent)
+	return t.base.RoundTrip(r)
+}
+
+// useSession points the client at the OAuth API with s's credentials.
+func (c *APIClient) useSession(s *session) {
+	c.session = s
+	c.baseURL = strings.TrimSuffix(appConfig.Auth.APIURL, "/")
+	c.client.Transport = &authTransport{base: http.DefaultTransport, session: s}
+}
+
+// Authenticated reports whether requests are made as a logged-in user.
+func (c *APIClient) Authenticated() bool {
+	return c.session != nil
+}
+
+// Username is the logged-in account's name, or "" when anonymous.
+func (c *APIClient) Username() string {
+	if c.session == nil {
+		return ""
+	}
+	return c.session.username()
+}
+
+// Me asks Reddit who the token belongs to.
+func (c *APIClient) Me() (string, error) {
+	if c.session == nil {
+		return "", errNotLoggedIn
+	}
+	resp, err := c.client.Get(c.baseURL + "/api/v1/me")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("identity: %s", resp.Status)
+	}
+	var me struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&me); err != nil {
+		return "", fmt.Errorf("failed to parse identity: %w", err)
+	}
+	return me.Name, nil
+}
+
+// postForm sends a form to the API as the logged-in user and returns the
+// reply. With api_type=json Reddit reports failures in the body as
+// [code, message, field] triples rather than through the status.
+func (c *APIClient) postForm(path string, form url.Values) ([]byte, error) {
+	if c.session == nil {
+		return nil, errNotLoggedIn
+	}
+	form.Set("api_type", "json")
+	resp, err := c.client.PostForm(c.baseURL+path, form)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", path, resp.Status)
+	}
+
+	var reply struct {
+		JSON struct {
+			Errors [][]interface{} `json:"errors"`
+		} `json:"json"`
+	}
+	if json.Unmarshal(data, &reply) == nil && len(reply.JSON.Errors) > 0 {
+		if e := reply.JSON.Errors[0]; len(e) > 1 {
+			return nil, fmt.Errorf("%v", e[1])
+		}
+		return nil, fmt.Errorf("%s failed", path)
+	}
+	return data, nil
+}
+
+// getJSON fetches path from the API as the logged-in user and decodes the
+// reply into v.
+func (c *APIClient) getJSON(path string, v interface{}) error {
+	if c.session == nil {
+		return errNotLoggedIn
+	}
+	resp, err := c.client.Get(c.baseURL + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// ============= Login =============
+
+// redirectHost is the host in the redirect URI. The login listener binds
+// every address it resolves to, so the browser finds it over IPv4 or IPv6.
+const redirectHost = "localhost"
+
+// redirectURI is the address Reddit sends the browser back to; it must be
+// the redirect URI registered for the app.
+func redirectURI() string {
+	return fmt.Sprintf("http://%s:%d/authorize_callback", redirectHost, appConfig.Auth.RedirectPort)
+}
+
+// openAuthPage shows Reddit's consent page; tests replace it.
+var openAuthPage = openURL
+
+// listenRedirect listens on port at each address redirectHost resolves to.
+// It fails only when none of them can be bound.
+func listenRedirect(port int) ([]net.Listener, error) {
+	addrs, err := net.LookupHost(redirectHost)
+	if err != nil {
+		return nil, err
+	}
+	var listeners []net.Listener
+	var firstErr error
+	for _, addr := range addrs {
+		ln, err := net.Listen("tcp", net.JoinHostPort(addr, fmt.Sprint(port)))
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		listeners = append(listeners, ln)
+	}
+	if len(listeners) == 0 {
+		if firstErr == nil {
+			firstErr = fmt.Errorf("%s has no addresses", redirectHost)
+		}
+		return nil, firstErr
+	}
+	return listeners, nil
+}
+
+// login runs the configured flow, saves the token and returns the account
+// name. Instructions for the user are written to out.
+func login(out io.Writer) (string, error) {
+	cfg := appConfig.Auth
+	if cfg.ClientID == "" {
+		return "", fmt.Errorf("auth.client_id is not set in config.json (see CONFIGURATION.md)")
+	}
+
+	var tok oauthToken
+	var err error
+	switch cfg.Flow {
+	case authFlowInstalled:
+		tok, err = authorizeInBrowser(out)
+	case authFlowScript:
+		tok, err = passwordGrant()
+	default:
+		return "", fmt.Errorf("unknown auth.flow %q (want installed or script)", cfg.Flow)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	s := &session{token: tok, path: tokenPath()}
+	c := NewAPIClient()
+	c.useSession(s)
+	name, err := c.Me()
+	if err != nil {
+		return "", err
+	}
+	s.token.Username = name
+	if err := saveToken(s.path, s.token); err != nil {
+		return "", fmt.Errorf("could not save token: %w", err)
+	}
+	return name, nil
+}
+
+// logout forgets the saved token.
+func logout() error {
+	err := os.Remove(tokenPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// authorizeInBrowser runs the installed-app flow: open Reddit's consent page
+// and wait for the redirect to the local listener with the code.
+func authorizeInBrowser(out io.Writer) (oauthToken, error) {
+	cfg := appConfig.Auth
+	state, err := randomState()
+	if err != nil {
+		return oauthToken{}, err
+	}
+
+	listeners, err := listenRedirect(cfg.RedirectPort)
+	if err != nil {
+		return oauthToken{}, fmt.Errorf("could not listen for the login redirect: %w", err)
+	}
+	type result struct {
+		code string
+		err  error
+	}
+	results := make(chan result, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize_callback", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("state") != state {
+			// Not Reddit's answer to this login; keep waiting for it
+			http.Error(w, "unexpected login redirect", http.StatusBadRequest)
+			return
+		}
+		var res result
+		switch {
+		case q.Get("error") != "":
+			res.err = fmt.Errorf("login refused: %s", q.Get("error"))
+		default:
+			res.code = q.Get("code")
+		}
+		if res.err != nil {
+			http.Error(w, res.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Logged in to redditview. You can close this tab.")
+		}
+		select {
+		case results <- res:
+		default:
+		}
+	})
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	for _, ln := range listeners {
+		go srv.Serve(ln)
+	}
+	defer srv.Shutdown(context.Background())
+
+	authURL := cfg.AuthURL + "?" + url.Values{
+		"client_id":     {cfg.ClientID},
+		"response_type": {"code"},
+		"state":         {state},
+		"redirect_uri":  {redirectURI()},
+		"duration":      {"permanent"},
+		"scope":         {strings.Join(cfg.Scopes, " ")},
+	}.Encode()
+	fmt.Fprintf(out, "Opening Reddit to approve access. If no browser opens, visit:\n\n  %s\n\n", authURL)
+	_ = openAuthPage(authURL)
+
+	var res result
+	select {
+	case res = <-results:
+	case <-time.After(loginTimeout):
+		return oauthToken{}, fmt.Errorf("timed out waiting for the login redirect")
+	}
+	if res.err != nil {
+		return oauthToken{}, res.err
+	}
+	return requestToken(url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {res.code},
+		"redirect_uri": {redirectURI()},
+	})
+}
+
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ============= Login Commands =============
+
+func runLogin(args []string, out io.Writer) error {
+	if len(args) > 0 {
+		return usageError{fmt.Errorf("login takes no arguments")}
+	}
+	name, err := login(out)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "Logged in as u/%s\n", name)
+	return err
+}
+
+func runLogout(args []string, out io.Writer) error {
+	if len(args) > 0 {
+		return usageError{fmt.Errorf("logout takes no arguments")}
+	}
+	if err := logout(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(out, "Logged out")
+	return err
+}
+
+func runWhoami(args []string, out io.Writer) error {
+	if len(args) > 0 {
+		return usageError{fmt.Errorf("whoami takes no arguments")}
+	}
+	s, err := loadSession()
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return errNotLoggedIn
+	}
+	c := NewAPIClient()
+	name, err := c.Me()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "u/%s\n", name)
+	return err
+}
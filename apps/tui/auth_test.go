@@ -0,0 +1,292 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeOAuth is a stand-in for Reddit's token endpoint. It hands out
+// access tokens "token-1", "token-2", ... and records the grants it saw.
+type fakeOAuth struct {
+	*httptest.Server
+	mu     sync.Mutex
+	issued int
+	grants []url.Values
+}
+
+func newFakeOAuth(t *testing.T) *fakeOAuth {
+	f := &fakeOAuth{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "client" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error": "401"}`)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("token request form: %v", err)
+		}
+		f.mu.Lock()
+		f.grants = append(f.grants, r.PostForm)
+		f.issued++
+		n := f.issued
+		f.mu.Unlock()
+
+		if r.PostForm.Get("code") == "bad" {
+			fmt.Fprint(w, `{"error": "invalid_grant"}`)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  fmt.Sprintf("token-%d", n),
+			"refresh_token": "refresh",
+			"token_type":    "bearer",
+			"expires_in":    3600,
+			"scope":         "identity read",
+		})
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeOAuth) lastGrant() url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.grants) == 0 {
+		return nil
+	}
+	return f.grants[len(f.grants)-1]
+}
+
+// useAuthConfig points the auth settings at tokenURL for one test.
+func useAuthConfig(t *testing.T, tokenURL string) {
+	saved := appConfig
+	t.Cleanup(func() { appConfig = saved })
+	appConfig = AppConfig{}
+	applyConfigDefaults()
+	appConfig.Auth.ClientID = "client"
+	appConfig.Auth.ClientSecret = "secret"
+	appConfig.Auth.TokenURL = tokenURL
+	appConfig.Auth.TokenFile = filepath.Join(t.TempDir(), "token.json")
+}
+
+func TestRequestToken(t *testing.T) {
+	f := newFakeOAuth(t)
+	useAuthConfig(t, f.URL)
+
+	before := clock.Now()
+	tok, err := requestToken(url.Values{"grant_type": {"authorization_code"}, "code": {"abc"}})
+	if err != nil {
+		t.Fatalf("requestToken: %v", err)
+	}
+	if tok.AccessToken != "token-1" || tok.RefreshToken != "refresh" || tok.Scope != "identity read" {
+		t.Errorf("token = %+v", tok)
+	}
+	if d := tok.Expiry.Sub(before); d < 59*time.Minute || d > 61*time.Minute {
+		t.Errorf("expiry is %v away, want about an hour", d)
+	}
+	if got := f.lastGrant().Get("grant_type"); got != "authorization_code" {
+		t.Errorf("grant_type = %q", got)
+	}
+
+	if _, err := requestToken(url.Values{"code": {"bad"}}); err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("error reply: err = %v, want invalid_grant", err)
+	}
+
+	appConfig.Auth.ClientSecret = "wrong"
+	if _, err := requestToken(url.Values{}); err == nil {
+		t.Error("bad client credentials: no error")
+	}
+}
+
+func TestSessionRefreshesExpiredToken(t *testing.T) {
+	f := newFakeOAuth(t)
+	useAuthConfig(t, f.URL)
+
+	s := &session{
+		token: oauthToken{
+			AccessToken:  "stale",
+			RefreshToken: "refresh",
+			Expiry:       clock.Now().Add(tokenRefreshMargin / 2),
+			Username:     "someone",
+		},
+		path: tokenPath(),
+	}
+	tok, err := s.accessToken(false)
+	if err != nil {
+		t.Fatalf("accessToken: %v", err)
+	}
+	if tok != "token-1" {
+		t.Errorf("access token = %q, want a renewed one", tok)
+	}
+	grant := f.lastGrant()
+	if grant.Get("grant_type") != "refresh_token" || grant.Get("refresh_token") != "refresh" {
+		t.Errorf("grant = %v", grant)
+	}
+
+	saved, err := loadToken(s.path)
+	if err != nil {
+		t.Fatalf("loadToken: %v", err)
+	}
+	if saved.AccessToken != "token-1" || saved.RefreshToken != "refresh" || saved.Username != "someone" {
+		t.Errorf("saved token = %+v", saved)
+	}
+
+	// A current token is used as is
+	if tok, err := s.accessToken(false); err != nil || tok != "token-1" {
+		t.Errorf("second accessToken = %q, %v", tok, err)
+	}
+	if f.issued != 1 {
+		t.Errorf("%d token requests, want 1", f.issued)
+	}
+}
+
+func TestAuthTransportRetriesOnUnauthorized(t *testing.T) {
+	f := newFakeOAuth(t)
+	useAuthConfig(t, f.URL)
+
+	var mu sync.Mutex
+	var seen []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		seen = append(seen, r.Header.Get("Authorization")+" "+string(body))
+		mu.Unlock()
+		if r.Header.Get("Authorization") != "bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer api.Close()
+
+	// Still valid by its expiry, but revoked on the server
+	s := &session{
+		token: oauthToken{AccessToken: "revoked", RefreshToken: "refresh", Expiry: clock.Now().Add(time.Hour)},
+		path:  tokenPath(),
+	}
+	client := &http.Client{Transport: &authTransport{base: http.DefaultTransport, session: s}}
+
+	resp, err := client.Post(api.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %s, want 200 after the retry", resp.Status)
+	}
+	want := []string{"bearer revoked payload", "bearer token-1 payload"}
+	if strings.Join(seen, "|") != strings.Join(want, "|") {
+		t.Errorf("requests = %q, want %q", seen, want)
+	}
+
+	// A second 401 is returned rather than retried again
+	s.token.AccessToken = "revoked"
+	seen = nil
+	resp, err = client.Get(api.URL)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized || len(seen) != 2 {
+		t.Errorf("status = %s after %d requests, want 401 after 2", resp.Status, len(seen))
+	}
+}
+
+func TestAuthorizeInBrowserChecksState(t *testing.T) {
+	f := newFakeOAuth(t)
+	useAuthConfig(t, f.URL)
+	appConfig.Auth.RedirectPort = freePort(t)
+
+	saved := openAuthPage
+	t.Cleanup(func() { openAuthPage = saved })
+	var strayStatus int
+	openAuthPage = func(authURL string) error {
+		u, err := url.Parse(authURL)
+		if err != nil {
+			return err
+		}
+		q := u.Query()
+		if q.Get("redirect_uri") != redirectURI() {
+			t.Errorf("redirect_uri = %q", q.Get("redirect_uri"))
+		}
+		go func() {
+			// A redirect that is not for this login is turned away...
+			resp, err := http.Get(redirectURI() + "?state=forged&code=evil")
+			if err != nil {
+				t.Errorf("stray redirect: %v", err)
+				return
+			}
+			resp.Body.Close()
+			strayStatus = resp.StatusCode
+			// ...and the login still completes with the real one
+			resp, err = http.Get(redirectURI() + "?" + url.Values{"state": {q.Get("state")}, "code": {"good"}}.Encode())
+			if err != nil {
+				t.Errorf("redirect: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+		return nil
+	}
+
+	tok, err := authorizeInBrowser(io.Discard)
+	if err != nil {
+		t.Fatalf("authorizeInBrowser: %v", err)
+	}
+	if strayStatus != http.StatusBadRequest {
+		t.Errorf("stray redirect status = %d, want 400", strayStatus)
+	}
+	if tok.AccessToken == "" {
+		t.Error("no access token")
+	}
+	grant := f.lastGrant()
+	if grant.Get("code") != "good" || grant.Get("redirect_uri") != redirectURI() {
+		t.Errorf("grant = %v", grant)
+	}
+}
+
+func TestAuthorizeInBrowserRefused(t *testing.T) {
+	f := newFakeOAuth(t)
+	useAuthConfig(t, f.URL)
+	appConfig.Auth.RedirectPort = freePort(t)
+
+	saved := openAuthPage
+	t.Cleanup(func() { openAuthPage = saved })
+	openAuthPage = func(authURL string) error {
+		u, _ := url.Parse(authURL)
+		go func() {
+			resp, err := http.Get(redirectURI() + "?" + url.Values{"state": {u.Query().Get("state")}, "error": {"access_denied"}}.Encode())
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+
+	if _, err := authorizeInBrowser(io.Discard); err == nil || !strings.Contains(err.Error(), "access_denied") {
+		t.Errorf("err = %v, want access_denied", err)
+	}
+	if f.issued != 0 {
+		t.Errorf("%d token requests after a refusal", f.issued)
+	}
+}
+
+// freePort returns a port on localhost that nothing is listening on.
+func freePort(t *testing.T) int {
+	ln, err := net.Listen("tcp", net.JoinHostPort(redirectHost, "0"))
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	return ln.Addr().(*net.TCPAddr).Port
+}
@@ -0,0 +1,408 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// ============= Command Line =============
+
+// Subcommands reuse the API client without the terminal UI and print to
+// stdout, so scripts and cron jobs can use them.
+
+const cliUsage = `Usage:
+  redditview                          browse the default subreddit
+  redditview <link or post ID>        open a post in the terminal UI
+  redditview list <subreddit> [--sort hot|new|top|controversial|rising] [--limit 1-100]
+  redditview search <query> [--limit 1-100]
+  redditview comments <link or post ID> [--sort best|top|new|controversial|old|qa]
+  redditview export <link or post ID> [--format markdown|html|json] [--dir DIR] [--sort ...] [--force]
+  redditview login                    log in with the app set up under "auth" in config.json
+  redditview logout                   forget the saved login
+  redditview whoami                   show the logged-in account
+
+Output flags (list, search, comments):
+  -o, --output plain|json|ndjson      output format (default plain)
+`
+
+// usageError is a mistake on the command line rather than a failure.
+type usageError struct {
+	err error
+}
+
+func (e usageError) Error() string { return e.err.Error() }
+
+var subcommands = map[string]func(args []string, out io.Writer) error{
+	"list":     runList,
+	"search":   runSearch,
+	"comments": runComments,
+	"export":   runExport,
+	"login":    runLogin,
+	"logout":   runLogout,
+	"whoami":   runWhoami,
+}
+
+// runCLI runs the subcommand named by args[0] and returns the process exit
+// status. ok is false when args[0] is not a subcommand.
+func runCLI(args []string) (status int, ok bool) {
+	if len(args) == 0 {
+		return 0, false
+	}
+	switch args[0] {
+	case "help", "-h", "--help":
+		fmt.Print(cliUsage)
+		return 0, true
+	}
+	run, ok := subcommands[args[0]]
+	if !ok {
+		return 0, false
+	}
+
+	err := run(args[1:], os.Stdout)
+	var usage usageError
+	switch {
+	case err == nil:
+		return 0, true
+	case errors.Is(err, flag.ErrHelp):
+		fmt.Print(cliUsage)
+		return 0, true
+	case errors.As(err, &usage):
+		fmt.Fprintf(os.Stderr, "redditview %s: %v\n\n%s", args[0], err, cliUsage)
+		return 2, true
+	}
+	fmt.Fprintf(os.Stderr, "redditview %s: %v\n", args[0], err)
+	return 1, true
+}
+
+// parseFlags parses fs from args, allowing flags after the positional
+// arguments, as in "list golang --sort top".
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	fs.SetOutput(io.Discard)
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, usageError{err}
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// outputFlag registers -o/--output on fs.
+func outputFlag(fs *flag.FlagSet) *string {
+	format := fs.String("output", "plain", "plain, json or ndjson")
+	fs.StringVar(format, "o", "plain", "plain, json or ndjson")
+	return format
+}
+
+func checkOutput(format string) error {
+	switch format {
+	case "plain", "json", "ndjson":
+		return nil
+	}
+	return usageError{fmt.Errorf("unknown output format %q (want plain, json or ndjson)", format)}
+}
+
+// maxLimit is the most posts Reddit returns for one listing request.
+const maxLimit = 100
+
+func checkLimit(limit int) error {
+	if limit < 1 || limit > maxLimit {
+		return usageError{fmt.Errorf("--limit must be between 1 and %d, not %d", maxLimit, limit)}
+	}
+	return nil
+}
+
+func runList(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	sort := fs.String("sort", "hot", "hot, new, top, controversial or rising")
+	limit := fs.Int("limit", 25, "number of posts")
+	format := outputFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := checkOutput(*format); err != nil {
+		return err
+	}
+	if err := checkLimit(*limit); err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usageError{fmt.Errorf("expected one subreddit")}
+	}
+	switch *sort {
+	case "hot", "new", "top", "controversial", "rising":
+	default:
+		return usageError{fmt.Errorf("unknown sort %q", *sort)}
+	}
+
+	subreddit := strings.TrimPrefix(strings.TrimPrefix(positional[0], "/"), "r/")
+	posts, err := NewAPIClient().FetchPosts(subreddit, *sort, *limit)
+	if err != nil {
+		return err
+	}
+	return writePosts(out, *format, posts, *limit)
+}
+
+func runSearch(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	limit := fs.Int("limit", 25, "number of posts")
+	format := outputFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := checkOutput(*format); err != nil {
+		return err
+	}
+	if err := checkLimit(*limit); err != nil {
+		return err
+	}
+	query := strings.TrimSpace(strings.Join(positional, " "))
+	if query == "" {
+		return usageError{fmt.Errorf("expected a search query")}
+	}
+
+	posts, err := NewAPIClient().SearchPosts(query, *limit)
+	if err != nil {
+		return err
+	}
+	return writePosts(out, *format, posts, *limit)
+}
+
+func runComments(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("comments", flag.ContinueOnError)
+	sort := fs.String("sort", appConfig.TUI.CommentSort, "best, top, new, controversial, old or qa")
+	format := outputFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := checkOutput(*format); err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usageError{fmt.Errorf("expected one post link or ID")}
+	}
+	if !validCommentSort(*sort) {
+		return usageError{fmt.Errorf("unknown sort %q", *sort)}
+	}
+	ref, err := parsePostRef(positional[0])
+	if err != nil {
+		return usageError{err}
+	}
+
+	post, comments, err := NewAPIClient().FetchThread(ref.subreddit, ref.id, *sort)
+	if err != nil {
+		return err
+	}
+	return writeThread(out, *format, post, comments)
+}
+
+// ============= Output =============
+
+// postJSON is the JSON form of a post in command output and exports.
+type postJSON struct {
+	Kind        string  `json:"kind"`
+	ID          string  `json:"id"`
+	Title       string  `json:"title"`
+	Author      string  `json:"author"`
+	Subreddit   string  `json:"subreddit"`
+	Score       int     `json:"score"`
+	UpvoteRatio float64 `json:"upvote_ratio,omitempty"`
+	NumComments int     `json:"num_comments"`
+	Created     string  `json:"created"`
+	CreatedUTC  float64 `json:"created_utc"`
+	URL         string  `json:"url,omitempty"`
+	Permalink   string  `json:"permalink"`
+	Domain      string  `json:"domain,omitempty"`
+	Flair       string  `json:"flair,omitempty"`
+	NSFW        bool    `json:"nsfw"`
+	Spoiler     bool    `json:"spoiler"`
+	Stickied    bool    `json:"stickied"`
+	Locked      bool    `json:"locked"`
+	SelfText    string  `json:"selftext,omitempty"`
+}
+
+// commentJSON is the JSON form of a comment. Nested output keeps replies
+// under their parent; NDJSON flattens them and names the parent instead.
+type commentJSON struct {
+	Kind       string        `json:"kind"`
+	ID         string        `json:"id"`
+	ParentID   string        `json:"parent_id,omitempty"`
+	Author     string        `json:"author"`
+	Body       string        `json:"body"`
+	Score      int           `json:"score"`
+	Created    string        `json:"created"`
+	CreatedUTC float64       `json:"created_utc"`
+	Depth      int           `json:"depth"`
+	Replies    []commentJSON `json:"replies,omitempty"`
+}
+
+func isoTime(created float64) string {
+	if created <= 0 {
+		return ""
+	}
+	return time.Unix(int64(created), 0).UTC().Format(time.RFC3339)
+}
+
+func newPostJSON(post RedditPostData) postJSON {
+	return postJSON{
+		Kind:        "t3",
+		ID:          post.ID,
+		Title:       post.Title,
+		Author:      post.Author,
+		Subreddit:   post.SubName,
+		Score:       post.Score,
+		UpvoteRatio: post.UpvoteRatio,
+		NumComments: post.Comments,
+		Created:     isoTime(post.Created),
+		CreatedUTC:  post.Created,
+		URL:         post.URL,
+		Permalink:   postPermalink(post),
+		Domain:      post.Domain,
+		Flair:       post.Flair,
+		NSFW:        post.Over18,
+		Spoiler:     post.Spoiler,
+		Stickied:    post.Stickied,
+		Locked:      post.Locked,
+		SelfText:    post.SelfText,
+	}
+}
+
+func newCommentJSON(c *Comment) commentJSON {
+	out := commentJSON{
+		Kind:       "t1",
+		ID:         c.ID,
+		Author:     c.Author,
+		Body:       c.Body,
+		Score:      c.Score,
+		Created:    isoTime(c.Created),
+		CreatedUTC: c.Created,
+		Depth:      c.Depth,
+	}
+	for _, r := range c.Replies {
+		out.Replies = append(out.Replies, newCommentJSON(r))
+	}
+	return out
+}
+
+// threadJSON is a post with its comment tree.
+type threadJSON struct {
+	Post     postJSON      `json:"post"`
+	Comments []commentJSON `json:"comments"`
+	// MissingComments is how many comments an export could not load
+	MissingComments int `json:"missing_comments,omitempty"`
+}
+
+func newThreadJSON(post RedditPostData, comments []*Comment) threadJSON {
+	thread := threadJSON{Post: newPostJSON(post), Comments: []commentJSON{}}
+	for _, c := range comments {
+		thread.Comments = append(thread.Comments, newCommentJSON(c))
+	}
+	return thread
+}
+
+func writePosts(out io.Writer, format string, posts []RedditPostData, limit int) error {
+	if limit > 0 && len(posts) > limit {
+		posts = posts[:limit]
+	}
+	switch format {
+	case "json":
+		list := make([]postJSON, 0, len(posts))
+		for _, post := range posts {
+			list = append(list, newPostJSON(post))
+		}
+		return writeJSON(out, list)
+	case "ndjson":
+		enc := json.NewEncoder(out)
+		enc.SetEscapeHTML(false)
+		for _, post := range posts {
+			if err := enc.Encode(newPostJSON(post)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, post := range posts {
+		age := formatTimestamp(post.Created, false)
+		_, err := fmt.Fprintf(out, "%-8s %6s %6s  %-8s  r/%s  %s\n",
+			post.ID, formatNum(post.Score), formatNum(post.Comments), age, post.SubName, post.Title)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeThread(out io.Writer, format string, post RedditPostData, comments []*Comment) error {
+	switch format {
+	case "json":
+		return writeJSON(out, newThreadJSON(post, comments))
+	case "ndjson":
+		// The post, then every comment depth first with its parent's ID
+		enc := json.NewEncoder(out)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(newPostJSON(post)); err != nil {
+			return err
+		}
+		var walk func(parent string, comments []*Comment) error
+		walk = func(parent string, comments []*Comment) error {
+			for _, c := range comments {
+				line := newCommentJSON(c)
+				line.ParentID, line.Replies = parent, nil
+				if err := enc.Encode(line); err != nil {
+					return err
+				}
+				if err := walk("t1_"+c.ID, c.Replies); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return walk("t3_"+post.ID, comments)
+	}
+
+	fmt.Fprintln(out, post.Title)
+	fmt.Fprintf(out, "r/%s  u/%s  %s points  %s comments  %s\n",
+		post.SubName, post.Author, formatNum(post.Score), formatNum(post.Comments), formatTimestamp(post.Created, false))
+	fmt.Fprintln(out, postPermalink(post))
+	if post.SelfText != "" {
+		fmt.Fprintf(out, "\n%s\n", wrapText(post.SelfText, 80))
+	}
+
+	var walk func(comments []*Comment)
+	walk = func(comments []*Comment) {
+		for _, c := range comments {
+			indent := strings.Repeat("  ", c.Depth)
+			fmt.Fprintf(out, "\n%su/%s  %s points  %s\n", indent, c.Author, formatNum(c.Score), formatTimestamp(c.Created, false))
+			for _, line := range strings.Split(wrapText(c.Body, max(20, 80-len(indent))), "\n") {
+				fmt.Fprintf(out, "%s  %s\n", indent, line)
+			}
+			walk(c.Replies)
+		}
+	}
+	walk(comments)
+	return nil
+}
+
+func writeJSON(out io.Writer, v interface{}) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
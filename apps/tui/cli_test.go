@@ -0,0 +1,269 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAPI is a stand-in for the proxy serving a few canned listings. It
+// records the request URIs it saw.
+type fakeAPI struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []string
+}
+
+const (
+	fakePosts = `{"kind": "Listing", "data": {"children": [
+		{"kind": "t3", "data": {"id": "aaa111", "title": "First <post>", "author": "alice", "subreddit": "golang",
+			"score": 1500, "num_comments": 12, "created_utc": 1714561200, "permalink": "/r/golang/comments/aaa111/first/"}},
+		{"kind": "t3", "data": {"id": "bbb222", "title": "Second", "author": "bob", "subreddit": "golang",
+			"score": 7, "num_comments": 0, "created_utc": 1714554000, "permalink": "/r/golang/comments/bbb222/second/"}},
+		{"kind": "t3", "data": {"id": "ccc333", "title": "Third", "author": "carol", "subreddit": "golang",
+			"score": 3, "num_comments": 1, "created_utc": 1714467600, "permalink": "/r/golang/comments/ccc333/third/"}}
+	]}}`
+	fakeThread = `[
+		{"kind": "Listing", "data": {"children": [
+			{"kind": "t3", "data": {"id": "aaa111", "title": "First <post>", "author": "alice", "subreddit": "golang",
+				"score": 1500, "num_comments": 2, "created_utc": 1714561200, "selftext": "Hello",
+				"permalink": "/r/golang/comments/aaa111/first/"}}
+		]}},
+		{"kind": "Listing", "data": {"children": [
+			{"kind": "t1", "data": {"id": "c1", "author": "bob", "body": "Top level", "score": 5, "created_utc": 1714562100,
+				"replies": {"kind": "Listing", "data": {"children": [
+					{"kind": "t1", "data": {"id": "c2", "author": "alice", "body": "A reply", "score": 2,
+						"created_utc": 1714562400, "replies": ""}}
+				]}}}}
+		]}}
+	]`
+)
+
+func newFakeAPI(t *testing.T) *fakeAPI {
+	f := &fakeAPI{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.requests = append(f.requests, r.URL.RequestURI())
+		f.mu.Unlock()
+
+		switch {
+		case r.URL.Path == "/search.json" || strings.HasPrefix(r.URL.Path, "/r/golang/") && strings.HasSuffix(r.URL.Path, ".json"):
+			fmt.Fprint(w, fakePosts)
+		case r.URL.Path == "/comments/aaa111/" || r.URL.Path == "/r/golang/comments/aaa111/":
+			fmt.Fprint(w, fakeThread)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeAPI) seen() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.requests...)
+}
+
+// useAPIConfig points the client at baseURL for one test, logged out.
+func useAPIConfig(t *testing.T, baseURL string) {
+	saved := appConfig
+	t.Cleanup(func() { appConfig = saved })
+	appConfig = AppConfig{}
+	applyConfigDefaults()
+	appConfig.API.BaseURL = baseURL
+	appConfig.Auth.TokenFile = filepath.Join(t.TempDir(), "token.json")
+}
+
+func TestCLIArgumentErrors(t *testing.T) {
+	api := newFakeAPI(t)
+	useAPIConfig(t, api.URL)
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"list without a subreddit", []string{"list"}, "expected one subreddit"},
+		{"list with two subreddits", []string{"list", "golang", "rust"}, "expected one subreddit"},
+		{"list with an unknown sort", []string{"list", "golang", "--sort", "best"}, `unknown sort "best"`},
+		{"list with a zero limit", []string{"list", "golang", "--limit", "0"}, "--limit must be between 1 and 100, not 0"},
+		{"list with a negative limit", []string{"list", "golang", "--limit=-5"}, "--limit must be between 1 and 100, not -5"},
+		{"list with too high a limit", []string{"list", "golang", "--limit", "101"}, "--limit must be between 1 and 100, not 101"},
+		{"list with a non-numeric limit", []string{"list", "golang", "--limit", "many"}, "invalid value"},
+		{"list with an unknown output", []string{"list", "golang", "-o", "csv"}, `unknown output format "csv"`},
+		{"list with an unknown flag", []string{"list", "golang", "--top"}, "flag provided but not defined"},
+		{"search without a query", []string{"search", " "}, "expected a search query"},
+		{"search with a zero limit", []string{"search", "go", "--limit", "0"}, "--limit must be between 1 and 100, not 0"},
+		{"search with too high a limit", []string{"search", "go", "--limit", "500"}, "--limit must be between 1 and 100, not 500"},
+		{"comments without a post", []string{"comments"}, "expected one post link or ID"},
+		{"comments with an unknown sort", []string{"comments", "aaa111", "--sort", "hot"}, `unknown sort "hot"`},
+		{"comments with a bad link", []string{"comments", "https://example.com/x"}, "not a Reddit link"},
+		{"comments with an unknown output", []string{"comments", "aaa111", "--output", "xml"}, `unknown output format "xml"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			err := subcommands[tt.args[0]](tt.args[1:], &out)
+			var usage usageError
+			if !errors.As(err, &usage) {
+				t.Fatalf("error = %v, want a usage error", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to mention %q", err, tt.want)
+			}
+			if out.Len() != 0 {
+				t.Errorf("printed %q on a usage error", out.String())
+			}
+		})
+	}
+	if seen := api.seen(); len(seen) != 0 {
+		t.Errorf("usage errors made requests: %v", seen)
+	}
+}
+
+func TestCLIOutput(t *testing.T) {
+	useClock(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name    string
+		args    []string
+		request string
+		want    string
+	}{
+		{
+			name:    "list plain",
+			args:    []string{"list", "r/golang", "--sort", "top", "--limit", "2"},
+			request: "/r/golang/top.json?limit=2",
+			want: "aaa111     1.5K     12  1h ago    r/golang  First <post>\n" +
+				"bbb222        7      0  3h ago    r/golang  Second\n",
+		},
+		{
+			name:    "list json",
+			args:    []string{"list", "golang", "-o", "json", "--limit", "1"},
+			request: "/r/golang/hot.json?limit=1",
+			want: `[
+  {
+    "kind": "t3",
+    "id": "aaa111",
+    "title": "First <post>",
+    "author": "alice",
+    "subreddit": "golang",
+    "score": 1500,
+    "num_comments": 12,
+    "created": "2024-05-01T11:00:00Z",
+    "created_utc": 1714561200,
+    "permalink": "https://reddit.com/r/golang/comments/aaa111/first/",
+    "nsfw": false,
+    "spoiler": false,
+    "stickied": false,
+    "locked": false
+  }
+]
+`,
+		},
+		{
+			name:    "search ndjson",
+			args:    []string{"search", "go", "generics", "--output=ndjson"},
+			request: "/search.json?q=go+generics&type=link&limit=25",
+			want: `{"kind":"t3","id":"aaa111","title":"First <post>","author":"alice","subreddit":"golang","score":1500,"num_comments":12,"created":"2024-05-01T11:00:00Z","created_utc":1714561200,"permalink":"https://reddit.com/r/golang/comments/aaa111/first/","nsfw":false,"spoiler":false,"stickied":false,"locked":false}
+{"kind":"t3","id":"bbb222","title":"Second","author":"bob","subreddit":"golang","score":7,"num_comments":0,"created":"2024-05-01T09:00:00Z","created_utc":1714554000,"permalink":"https://reddit.com/r/golang/comments/bbb222/second/","nsfw":false,"spoiler":false,"stickied":false,"locked":false}
+{"kind":"t3","id":"ccc333","title":"Third","author":"carol","subreddit":"golang","score":3,"num_comments":1,"created":"2024-04-30T09:00:00Z","created_utc":1714467600,"permalink":"https://reddit.com/r/golang/comments/ccc333/third/","nsfw":false,"spoiler":false,"stickied":false,"locked":false}
+`,
+		},
+		{
+			name:    "comments plain",
+			args:    []string{"comments", "https://www.reddit.com/r/golang/comments/aaa111/first/", "--sort", "new"},
+			request: "/r/golang/comments/aaa111/?sort=new&limit=500",
+			want: "First <post>\n" +
+				"r/golang  u/alice  1.5K points  2 comments  1h ago\n" +
+				"https://reddit.com/r/golang/comments/aaa111/first/\n" +
+				"\nHello\n" +
+				"\nu/bob  5 points  45m ago\n" +
+				"  Top level\n" +
+				"\n  u/alice  2 points  40m ago\n" +
+				"    A reply\n",
+		},
+		{
+			name:    "comments json",
+			args:    []string{"comments", "aaa111", "-o", "json", "--sort", "top"},
+			request: "/comments/aaa111/?sort=top&limit=500",
+			want: `{
+  "post": {
+    "kind": "t3",
+    "id": "aaa111",
+    "title": "First <post>",
+    "author": "alice",
+    "subreddit": "golang",
+    "score": 1500,
+    "num_comments": 2,
+    "created": "2024-05-01T11:00:00Z",
+    "created_utc": 1714561200,
+    "permalink": "https://reddit.com/r/golang/comments/aaa111/first/",
+    "nsfw": false,
+    "spoiler": false,
+    "stickied": false,
+    "locked": false,
+    "selftext": "Hello"
+  },
+  "comments": [
+    {
+      "kind": "t1",
+      "id": "c1",
+      "author": "bob",
+      "body": "Top level",
+      "score": 5,
+      "created": "2024-05-01T11:15:00Z",
+      "created_utc": 1714562100,
+      "depth": 0,
+      "replies": [
+        {
+          "kind": "t1",
+          "id": "c2",
+          "author": "alice",
+          "body": "A reply",
+          "score": 2,
+          "created": "2024-05-01T11:20:00Z",
+          "created_utc": 1714562400,
+          "depth": 1
+        }
+      ]
+    }
+  ]
+}
+`,
+		},
+		{
+			name:    "comments ndjson",
+			args:    []string{"comments", "t3_aaa111", "-o", "ndjson", "--sort", "top"},
+			request: "/comments/aaa111/?sort=top&limit=500",
+			want: `{"kind":"t3","id":"aaa111","title":"First <post>","author":"alice","subreddit":"golang","score":1500,"num_comments":2,"created":"2024-05-01T11:00:00Z","created_utc":1714561200,"permalink":"https://reddit.com/r/golang/comments/aaa111/first/","nsfw":false,"spoiler":false,"stickied":false,"locked":false,"selftext":"Hello"}
+{"kind":"t1","id":"c1","parent_id":"t3_aaa111","author":"bob","body":"Top level","score":5,"created":"2024-05-01T11:15:00Z","created_utc":1714562100,"depth":0}
+{"kind":"t1","id":"c2","parent_id":"t1_c1","author":"alice","body":"A reply","score":2,"created":"2024-05-01T11:20:00Z","created_utc":1714562400,"depth":1}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newFakeAPI(t)
+			useAPIConfig(t, api.URL)
+
+			var out strings.Builder
+			if err := subcommands[tt.args[0]](tt.args[1:], &out); err != nil {
+				t.Fatalf("%v: %v", tt.args, err)
+			}
+			if seen := api.seen(); len(seen) != 1 || seen[0] != tt.request {
+				t.Errorf("requests = %v, want [%s]", seen, tt.request)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("output =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
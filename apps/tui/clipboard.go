@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	osc52 "github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-isatty"
+)
+
+// ============= Clipboard =============
+
+const toastDuration = 2 * time.Second
+
+// copyToClipboard puts text on the system clipboard. The OSC 52 escape is
+// always emitted when attached to a terminal so that copying works over SSH;
+// the native clipboard is used as a fallback for local sessions and for
+// terminals that are not attached to a tty.
+func copyToClipboard(text string) error {
+	if text == "" {
+		return fmt.Errorf("nothing to copy")
+	}
+
+	sentOSC52 := false
+	if isatty.IsTerminal(os.Stderr.Fd()) && os.Getenv("TERM") != "dumb" {
+		seq := osc52.New(text)
+		if os.Getenv("TMUX") != "" {
+			seq = seq.Tmux()
+		} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+			seq = seq.Screen()
+		}
+		// Written to stderr so it does not interleave with the renderer's frames
+		if _, err := seq.WriteTo(os.Stderr); err == nil {
+			sentOSC52 = true
+		}
+	}
+
+	if isSSHSession() || clipboard.Unsupported {
+		if !sentOSC52 {
+			return fmt.Errorf("no clipboard available")
+		}
+		return nil
+	}
+
+	if err := clipboard.WriteAll(text); err != nil && !sentOSC52 {
+		return err
+	}
+	return nil
+}
+
+type copiedMsg struct {
+	label string
+	error error
+}
+
+// copyCmd copies text off the event loop, since the clipboard helpers
+// (xclip, xsel, wl-copy) are separate processes, and reports back with a
+// copiedMsg.
+func copyCmd(text, label string) tea.Cmd {
+	return func() tea.Msg {
+		return copiedMsg{label, copyToClipboard(text)}
+	}
+}
+
+func isSSHSession() bool {
+	return os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+}
+
+// postPermalink returns the absolute reddit.com URL for a post.
+func postPermalink(post RedditPostData) string {
+	if post.Permalink == "" {
+		return ""
+	}
+	if strings.HasPrefix(post.Permalink, "/") {
+		return "https://reddit.com" + post.Permalink
+	}
+	return post.Permalink
+}
+
+// markdownLink formats a post as a "[title](link)" snippet for chat.
+func markdownLink(post RedditPostData) string {
+	link := postPermalink(post)
+	if link == "" {
+		link = post.URL
+	}
+	title := strings.NewReplacer("[", "\\[", "]", "\\]").Replace(post.Title)
+	return fmt.Sprintf("[%s](%s)", title, link)
+}
+
+// ============= Toasts =============
+
+type toastExpiredMsg struct {
+	id int
+}
+
+// showToast displays a short-lived message in the info bar.
+func (m *Model) showToast(text string) tea.Cmd {
+	m.toastID++
+	m.toast = text
+	id := m.toastID
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return toastExpiredMsg{id}
+	})
+}
+
+// handleYank copies the part of the current selection named by key, the
+// second key of a "y" sequence.
+func (m Model) handleYank(key string) (Model, tea.Cmd) {
+	if len(m.filteredPosts) == 0 || m.list.Index() >= len(m.filteredPosts) {
+		return m, nil
+	}
+	post := m.filteredPosts[m.list.Index()]
+
+	var text, label string
+	switch key {
+	case "y", "p":
+		text, label = postPermalink(post), "permalink"
+	case "u":
+		text, label = post.URL, "URL"
+	case "t":
+		text, label = post.Title, "title"
+	case "m":
+		text, label = markdownLink(post), "markdown link"
+	case "c":
+		if !m.showComments && !m.reading {
+			return m, m.showToast("Open comments to copy a comment")
+		}
+		comment := m.focusedComment()
+		if comment == nil {
+			return m, m.showToast("No comment to copy")
+		}
+		text, label = comment.Body, fmt.Sprintf("comment by u/%s", comment.Author)
+	default:
+		return m, nil
+	}
+
+	return m, copyCmd(text, label)
+}
+
+// focusedComment returns the comment shown at the top of the comments panel
+// or the reader.
+func (m Model) focusedComment() *Comment {
+	scroll := m.commentsScrollY
+	if m.reading {
+		_, commentsAt := m.readerLines()
+		scroll = m.reader.YOffset - commentsAt - 2
+	}
+
+	_, starts := m.commentLines()
+	flat := m.flatComments()
+	for i := len(starts) - 1; i >= 0; i-- {
+		if starts[i] <= scroll {
+			return flat[i]
+		}
+	}
+	if len(flat) > 0 {
+		return flat[0]
+	}
+	return nil
+}
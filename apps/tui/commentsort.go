@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ============= Comment Sort =============
+
+// commentSorts are the comment orders offered, in the order the sort key
+// cycles through them.
+var commentSorts = []string{"best", "top", "new", "controversial", "old", "qa"}
+
+var commentSortLabels = map[string]string{
+	"best":          "Best",
+	"top":           "Top",
+	"new":           "New",
+	"controversial": "Controversial",
+	"old":           "Old",
+	"qa":            "Q&A",
+}
+
+// validCommentSort reports whether s is one of commentSorts.
+func validCommentSort(s string) bool {
+	_, ok := commentSortLabels[s]
+	return ok
+}
+
+// commentSortParam is the value Reddit's API expects for a sort; "best" is
+// called "confidence" there.
+func commentSortParam(s string) string {
+	if s == "best" {
+		return "confidence"
+	}
+	return s
+}
+
+func commentSortLabel(s string) string {
+	if label, ok := commentSortLabels[s]; ok {
+		return label
+	}
+	return s
+}
+
+// nextCommentSort returns the sort after s in the cycle.
+func nextCommentSort(s string) string {
+	for i, sort := range commentSorts {
+		if sort == s {
+			return commentSorts[(i+1)%len(commentSorts)]
+		}
+	}
+	return commentSorts[0]
+}
+
+// cycleCommentSort switches to the next comment order for the rest of the
+// session. Open comments are reloaded in the new order.
+func (m *Model) cycleCommentSort() {
+	m.commentSort = nextCommentSort(m.commentSort)
+	m.commentsPostID = "" // refetch on the next syncComments
+	m.commentsScrollY = 0
+	m.reader.YOffset = 0
+}
+
+// commentSortHint is the sort shown next to the comments heading.
+func (m Model) commentSortHint() string {
+	hint := fmt.Sprintf("sorted by %s", commentSortLabel(m.commentSort))
+	if m.following != "" && m.following == m.commentsPostID {
+		hint += "  •  👁 following"
+	}
+	return hint
+}
+
+// commentSortChoices lists the sorts for messages, marking the active one.
+func commentSortChoices(active string) string {
+	labels := make([]string, len(commentSorts))
+	for i, s := range commentSorts {
+		labels[i] = commentSortLabel(s)
+		if s == active {
+			labels[i] = "[" + labels[i] + "]"
+		}
+	}
+	return strings.Join(labels, " ")
+}
@@ -0,0 +1,375 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ============= Composer =============
+
+// The composer writes a reply to the selected post or the focused comment.
+// It fills the content area with the text being replied to above a
+// textarea, which can be swapped for a preview of the rendered markdown or
+// handed to $EDITOR. A sent reply is added to the comment tree in place,
+// without reloading the thread.
+
+// replyTarget is the post or comment a reply is to.
+type replyTarget struct {
+	postID   string
+	fullname string // t3_ for the post, t1_ for a comment
+	label    string
+	quote    string
+}
+
+func newComposer() textarea.Model {
+	ta := textarea.New()
+	ta.Placeholder = "Write your reply (markdown)..."
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 10000
+	ta.MaxHeight = 0
+	return ta
+}
+
+// startReply opens the composer on the target, keeping the draft when it is
+// reopened on the same target.
+func (m *Model) startReply() tea.Cmd {
+	if !m.client.Authenticated() {
+		return m.showToast("⚠ Log in to reply (redditview login)")
+	}
+	post, comment := m.target()
+	if post == nil {
+		return nil
+	}
+	if post.Locked {
+		return m.showToast("🔒 This thread is locked")
+	}
+
+	to := replyTarget{
+		postID:   post.ID,
+		fullname: "t3_" + post.ID,
+		label:    "post: " + post.Title,
+		quote:    post.SelfText,
+	}
+	if comment != nil {
+		to = replyTarget{
+			postID:   post.ID,
+			fullname: "t1_" + comment.ID,
+			label:    "u/" + comment.Author,
+			quote:    comment.Body,
+		}
+	}
+	if to.fullname != m.replyTo.fullname {
+		m.composer.Reset()
+	}
+	m.replyTo = to
+	m.composing = true
+	m.composePreview = false
+	m.sizeComposer()
+	return m.composer.Focus()
+}
+
+// composerRows is the height of the content area the composer fills.
+func (m Model) composerRows() int {
+	return max(1, m.windowHeight-chromeRows)
+}
+
+// quoteLines is the excerpt of the text being replied to.
+func (m Model) quoteLines() []string {
+	const maxQuote = 3
+	text := strings.TrimSpace(m.replyTo.quote)
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(wrapText(text, max(20, m.windowWidth-6)), "\n")
+	if len(lines) > maxQuote {
+		lines = append(lines[:maxQuote-1], "…")
+	}
+	return lines
+}
+
+func (m *Model) sizeComposer() {
+	used := 2 // heading and the blank line above the text
+	if quote := m.quoteLines(); len(quote) > 0 {
+		used += len(quote)
+	}
+	m.composer.SetWidth(max(20, m.windowWidth-2))
+	m.composer.SetHeight(max(3, m.composerRows()-used))
+}
+
+func (m Model) renderComposer() string {
+	heading := "✍ Reply to " + m.replyTo.label
+	if m.composePreview {
+		heading += "  (preview)"
+	}
+	lines := []string{styles.Focused.Render(truncateTitle(heading, m.windowWidth-2))}
+	for _, line := range m.quoteLines() {
+		lines = append(lines, styles.Meta.Render("│ "+line))
+	}
+	lines = append(lines, "")
+
+	if m.composePreview {
+		preview := renderMarkdown(m.composer.Value(), max(20, m.windowWidth-4))
+		if len(preview) == 0 {
+			preview = []string{styles.Meta.Render("Nothing to preview yet")}
+		}
+		lines = append(lines, preview...)
+	} else {
+		lines = append(lines, m.composer.View())
+	}
+
+	return styles.Body.
+		Padding(0, 1).
+		Height(m.composerRows()).
+		MaxHeight(m.composerRows()).
+		Render(strings.Join(lines, "\n"))
+}
+
+// truncateTitle cuts s to width columns.
+func truncateTitle(s string, width int) string {
+	r := []rune(s)
+	if width <= 1 || len(r) <= width {
+		return s
+	}
+	return string(r[:width-1]) + "…"
+}
+
+type replySentMsg struct {
+	to      replyTarget
+	comment *Comment
+	error   error
+}
+
+type editorDoneMsg struct {
+	text  string
+	error error
+}
+
+// handleComposerKey drives the composer: submit, preview, editor and
+// cancel, with everything else typed into the textarea.
+func (m Model) handleComposerKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Cancel):
+		m.composing = false
+		m.composer.Blur()
+		if strings.TrimSpace(m.composer.Value()) == "" {
+			return m, nil
+		}
+		return m, m.showToast("Reply kept as a draft")
+	case key.Matches(msg, m.keys.Preview):
+		m.composePreview = !m.composePreview
+		return m, nil
+	case key.Matches(msg, m.keys.Editor):
+		return m, m.openEditor()
+	case key.Matches(msg, m.keys.Submit):
+		text := strings.TrimSpace(m.composer.Value())
+		if text == "" {
+			return m, m.showToast("⚠ Reply is empty")
+		}
+		if m.submitting {
+			return m, nil
+		}
+		m.submitting = true
+		to, client := m.replyTo, m.client
+		send := func() tea.Msg {
+			c, err := client.Reply(to.fullname, text)
+			return replySentMsg{to, c, err}
+		}
+		return m, tea.Batch(m.showToast("✍ Sending reply..."), send)
+	}
+	if m.composePreview {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.composer, cmd = m.composer.Update(msg)
+	return m, cmd
+}
+
+// openEditor hands the draft to $VISUAL or $EDITOR (vi by default) and
+// reads it back when the editor exits.
+func (m Model) openEditor() tea.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	f, err := os.CreateTemp("", "redditview-reply-*.md")
+	if err != nil {
+		return func() tea.Msg { return editorDoneMsg{"", err} }
+	}
+	_, err = f.WriteString(m.composer.Value())
+	f.Close()
+	if err != nil {
+		os.Remove(f.Name())
+		return func() tea.Msg { return editorDoneMsg{"", err} }
+	}
+
+	args := append(strings.Fields(editor), f.Name())
+	cmd := exec.Command(args[0], args[1:]...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		defer os.Remove(f.Name())
+		if err != nil {
+			return editorDoneMsg{"", fmt.Errorf("%s: %w", editor, err)}
+		}
+		data, err := os.ReadFile(f.Name())
+		return editorDoneMsg{strings.TrimRight(string(data), "\n"), err}
+	})
+}
+
+// insertReply adds a sent reply to the loaded comment tree: first among the
+// post's comments, or first under the comment it answers.
+func (m *Model) insertReply(to replyTarget, c *Comment) {
+	m.addCommentCount(to.postID, 1)
+	if m.commentsPostID != to.postID {
+		return // comments not loaded; they will include it when fetched
+	}
+
+	if strings.HasPrefix(to.fullname, "t3_") {
+		c.Depth = 0
+		m.comments = append([]*Comment{c}, m.comments...)
+	}
+	for _, parent := range m.flatComments() {
+		if "t1_"+parent.ID == to.fullname {
+			c.Depth = parent.Depth + 1
+			parent.Replies = append([]*Comment{c}, parent.Replies...)
+			break
+		}
+	}
+	m.relayout()
+}
+
+// ============= Reply API =============
+
+// Reply posts text as a reply to a post ("t3_...") or comment ("t1_...")
+// and returns the new comment.
+func (c *APIClient) Reply(parent, text string) (*Comment, error) {
+	data, err := c.postForm("/api/comment", url.Values{
+		"thing_id": {parent},
+		"text":     {text},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var reply struct {
+		JSON struct {
+			Data struct {
+				Things []struct {
+					Kind string                 `json:"kind"`
+					Data map[string]interface{} `json:"data"`
+				} `json:"things"`
+			} `json:"data"`
+		} `json:"json"`
+	}
+	if err := json.Unmarshal(data, &reply); err != nil {
+		return nil, fmt.Errorf("failed to parse reply: %w", err)
+	}
+	for _, thing := range reply.JSON.Data.Things {
+		if thing.Kind != "t1" {
+			continue
+		}
+		d := thing.Data
+		return &Comment{
+			ID:      toString(d["id"]),
+			Author:  toString(d["author"]),
+			Body:    toString(d["body"]),
+			Score:   max(1, toInt(d["score"])),
+			Created: toFloat(d["created_utc"]),
+			Likes:   voteUp, // your own comments start upvoted
+		}, nil
+	}
+	return nil, fmt.Errorf("reply sent, but Reddit did not return it")
+}
+
+// ============= Markdown Preview =============
+
+var (
+	mdBold   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	mdItalic = regexp.MustCompile(`\*([^*\s][^*]*)\*|\b_([^_\s][^_]*)_\b`)
+	mdStrike = regexp.MustCompile(`~~([^~]+)~~`)
+	mdCode   = regexp.MustCompile("`([^`]+)`")
+	mdLink   = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdList   = regexp.MustCompile(`^(\s*)([-*+]|\d+\.)\s+`)
+)
+
+// renderMarkdown renders the parts of Reddit's markdown that matter in a
+// terminal: headings, quotes, lists, code and inline emphasis.
+func renderMarkdown(text string, width int) []string {
+	var out []string
+	inCode := false
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode || strings.HasPrefix(line, "    ") {
+			out = append(out, styles.Meta.Render("  "+strings.TrimPrefix(line, "    ")))
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, "#"):
+			heading := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			out = append(out, styles.Focused.Render(renderInline(heading)))
+		case strings.HasPrefix(trimmed, ">"):
+			quote := strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+			for _, l := range strings.Split(wrapText(quote, max(10, width-2)), "\n") {
+				out = append(out, styles.Meta.Render("│ "+renderInline(l)))
+			}
+		case trimmed == "---" || trimmed == "***":
+			out = append(out, styles.Separator.Render(strings.Repeat("─", max(1, width))))
+		case mdList.MatchString(line):
+			m := mdList.FindStringSubmatch(line)
+			bullet := "• "
+			if strings.HasSuffix(m[2], ".") {
+				bullet = m[2] + " "
+			}
+			indent := strings.Repeat(" ", len(m[1]))
+			body := wrapText(line[len(m[0]):], max(10, width-len(indent)-len(bullet)))
+			for i, l := range strings.Split(body, "\n") {
+				prefix := indent + bullet
+				if i > 0 {
+					prefix = indent + strings.Repeat(" ", len(bullet))
+				}
+				out = append(out, prefix+renderInline(l))
+			}
+		case trimmed == "":
+			out = append(out, "")
+		default:
+			for _, l := range strings.Split(wrapText(trimmed, width), "\n") {
+				out = append(out, renderInline(l))
+			}
+		}
+	}
+	return out
+}
+
+// renderInline styles emphasis, code and links within one line.
+func renderInline(s string) string {
+	s = mdLink.ReplaceAllString(s, "$1 ($2)")
+	s = mdCode.ReplaceAllStringFunc(s, func(m string) string {
+		return styles.Meta.Render(strings.Trim(m, "`"))
+	})
+	s = mdBold.ReplaceAllStringFunc(s, func(m string) string {
+		return styles.Focused.Render(m[2 : len(m)-2])
+	})
+	s = mdStrike.ReplaceAllStringFunc(s, func(m string) string {
+		return styles.Meta.Strikethrough(true).Render(m[2 : len(m)-2])
+	})
+	s = mdItalic.ReplaceAllStringFunc(s, func(m string) string {
+		return styles.Body.Italic(true).Render(m[1 : len(m)-1])
+	})
+	return s
+}
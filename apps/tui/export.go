@@ -0,0 +1,350 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ============= Export =============
+
+// Exports write a post with its whole comment tree to a file, for archiving
+// threads worth keeping. Comments Reddit would not hand over are counted,
+// and the export says how many are missing.
+
+// exportFormats maps each format to its file extension.
+var exportFormats = map[string]string{
+	"markdown": "md",
+	"html":     "html",
+	"json":     "json",
+}
+
+func validExportFormat(format string) bool {
+	_, ok := exportFormats[format]
+	return ok
+}
+
+const exportTimeLayout = "2006-01-02 15:04 UTC"
+
+func exportTime(created float64) string {
+	if created <= 0 {
+		return ""
+	}
+	return time.Unix(int64(created), 0).UTC().Format(exportTimeLayout)
+}
+
+// exportDir is where exports are written: export_dir from the config, with
+// a leading "~" expanded, or ~/redditview-exports.
+func exportDir() string {
+	home, _ := os.UserHomeDir()
+	dir := appConfig.TUI.ExportDir
+	switch {
+	case dir == "":
+		if home == "" {
+			return "redditview-exports"
+		}
+		return filepath.Join(home, "redditview-exports")
+	case dir == "~" || strings.HasPrefix(dir, "~/"):
+		return filepath.Join(home, dir[1:])
+	}
+	return dir
+}
+
+// exportFilename names an export "<subreddit>-<id>-<title>.<ext>", keeping
+// only lowercase letters, digits and dashes so the name is safe on any
+// filesystem. A title with nothing left, such as one in another script,
+// leaves "<subreddit>-<id>.<ext>".
+func exportFilename(post RedditPostData, format string) string {
+	name := slugify(post.ID, 20)
+	if name == "" {
+		name = "post"
+	}
+	if sub := slugify(post.SubName, 30); sub != "" {
+		name = sub + "-" + name
+	}
+	if slug := slugify(post.Title, 60); slug != "" {
+		name += "-" + slug
+	}
+	return name + "." + exportFormats[format]
+}
+
+// slugify lowercases s and replaces each run of other characters with a
+// dash, cutting it to at most n bytes.
+func slugify(s string, n int) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			if b.Len() >= n {
+				break
+			}
+		} else {
+			dash = true
+		}
+	}
+	return strings.Trim(b.String()[:min(b.Len(), n)], "-")
+}
+
+// exportThread writes post and comments to dir in format and returns the
+// file's path. missing is how many comments could not be loaded. An
+// existing file is only replaced when force is set; otherwise the export
+// gets a numbered name beside it.
+func exportThread(post RedditPostData, comments []*Comment, missing int, format, dir string, force bool) (string, error) {
+	var content string
+	switch format {
+	case "markdown":
+		content = threadMarkdown(post, comments, missing)
+	case "html":
+		content = threadHTML(post, comments, missing)
+	case "json":
+		thread := newThreadJSON(post, comments)
+		thread.MissingComments = missing
+		var b strings.Builder
+		if err := writeJSON(&b, thread); err != nil {
+			return "", err
+		}
+		content = b.String()
+	default:
+		return "", fmt.Errorf("unknown export format %q", format)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, exportFilename(post, format))
+	if force {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return "", err
+		}
+		return path, nil
+	}
+	return writeNewFile(path, []byte(content))
+}
+
+// maxExportCopies bounds the numbered names tried for one export.
+const maxExportCopies = 1000
+
+// writeNewFile writes data to path, or to "<name>-2<ext>", "<name>-3<ext>"
+// and so on when path already exists, and returns the path written.
+func writeNewFile(path string, data []byte) (string, error) {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for n := 1; n <= maxExportCopies; n++ {
+		name := path
+		if n > 1 {
+			name = fmt.Sprintf("%s-%d%s", base, n, ext)
+		}
+		f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err := f.Write(data); err != nil {
+			f.Close()
+			return "", err
+		}
+		return name, f.Close()
+	}
+	return "", fmt.Errorf("%s and %d numbered copies already exist", path, maxExportCopies-1)
+}
+
+// missingNote says how many comments an export leaves out, or is empty
+// when it has them all.
+func missingNote(missing int) string {
+	switch {
+	case missing == 0:
+		return ""
+	case missing == 1:
+		return "1 comment could not be loaded from Reddit and is not in this export."
+	}
+	return fmt.Sprintf("%d comments could not be loaded from Reddit and are not in this export.", missing)
+}
+
+// countComments counts comments including all replies.
+func countComments(comments []*Comment) int {
+	n := len(comments)
+	for _, c := range comments {
+		n += countComments(c.Replies)
+	}
+	return n
+}
+
+func threadMarkdown(post RedditPostData, comments []*Comment, missing int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", post.Title)
+	meta := []string{"r/" + post.SubName, "u/" + post.Author, fmt.Sprintf("%d points", post.Score),
+		fmt.Sprintf("%d comments", post.Comments), exportTime(post.Created)}
+	if post.Flair != "" {
+		meta = append(meta, "flair: "+post.Flair)
+	}
+	fmt.Fprintf(&b, "%s\n\n", strings.Join(meta, " · "))
+	if link := postPermalink(post); link != "" {
+		fmt.Fprintf(&b, "<%s>\n\n", link)
+	}
+	if !post.IsSelf && post.URL != "" && post.URL != postPermalink(post) {
+		fmt.Fprintf(&b, "Link: <%s>\n\n", post.URL)
+	}
+	if post.SelfText != "" {
+		fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(post.SelfText))
+	}
+
+	fmt.Fprintf(&b, "---\n\n## Comments (%d)\n", countComments(comments))
+	if note := missingNote(missing); note != "" {
+		fmt.Fprintf(&b, "\n> **Incomplete:** %s\n", note)
+	}
+	var walk func(comments []*Comment)
+	walk = func(comments []*Comment) {
+		for _, c := range comments {
+			// Each comment is a list item, nested under its parent
+			indent := strings.Repeat("  ", c.Depth)
+			fmt.Fprintf(&b, "\n%s- **u/%s** · %d points · %s\n", indent, c.Author, c.Score, exportTime(c.Created))
+			for _, line := range strings.Split(strings.TrimSpace(c.Body), "\n") {
+				if strings.TrimSpace(line) == "" {
+					b.WriteString("\n")
+					continue
+				}
+				fmt.Fprintf(&b, "\n%s  %s", indent, line)
+			}
+			b.WriteString("\n")
+			walk(c.Replies)
+		}
+	}
+	walk(comments)
+	return b.String()
+}
+
+const exportCSS = `body { font-family: system-ui, sans-serif; max-width: 50rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; color: #1a1a1b; }
+.meta { color: #787c7e; font-size: 0.9em; }
+.body { white-space: pre-wrap; }
+.comment { border-left: 2px solid #edeff1; margin: 0.75rem 0 0 0.5rem; padding-left: 0.75rem; }
+.comment .author { font-weight: 600; }
+.missing { background: #fff4e5; border: 1px solid #ffb74d; padding: 0.5rem 0.75rem; }
+`
+
+// threadHTML renders a standalone page with the comments nested as boxes
+// inside their parents.
+func threadHTML(post RedditPostData, comments []*Comment, missing int) string {
+	esc := html.EscapeString
+	var b strings.Builder
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n<style>\n%s</style>\n</head>\n<body>\n",
+		esc(post.Title), exportCSS)
+
+	fmt.Fprintf(&b, "<article>\n<h1>%s</h1>\n", esc(post.Title))
+	fmt.Fprintf(&b, "<p class=\"meta\">r/%s · u/%s · %d points · %d comments · <time datetime=\"%s\">%s</time>",
+		esc(post.SubName), esc(post.Author), post.Score, post.Comments, isoTime(post.Created), exportTime(post.Created))
+	if post.Flair != "" {
+		fmt.Fprintf(&b, " · %s", esc(post.Flair))
+	}
+	b.WriteString("</p>\n")
+	if link := postPermalink(post); link != "" {
+		fmt.Fprintf(&b, "<p><a href=\"%s\">%s</a></p>\n", esc(link), esc(link))
+	}
+	if !post.IsSelf && post.URL != "" && post.URL != postPermalink(post) {
+		fmt.Fprintf(&b, "<p>Link: <a href=\"%s\">%s</a></p>\n", esc(post.URL), esc(post.URL))
+	}
+	if post.SelfText != "" {
+		fmt.Fprintf(&b, "<div class=\"body\">%s</div>\n", esc(strings.TrimSpace(post.SelfText)))
+	}
+	b.WriteString("</article>\n")
+
+	fmt.Fprintf(&b, "<hr>\n<h2>Comments (%d)</h2>\n", countComments(comments))
+	if note := missingNote(missing); note != "" {
+		fmt.Fprintf(&b, "<p class=\"missing\"><strong>Incomplete:</strong> %s</p>\n", esc(note))
+	}
+	var walk func(comments []*Comment)
+	walk = func(comments []*Comment) {
+		for _, c := range comments {
+			fmt.Fprintf(&b, "<div class=\"comment\" id=\"%s\">\n", esc(c.ID))
+			fmt.Fprintf(&b, "<p class=\"meta\"><span class=\"author\">u/%s</span> · %d points · <time datetime=\"%s\">%s</time></p>\n",
+				esc(c.Author), c.Score, isoTime(c.Created), exportTime(c.Created))
+			fmt.Fprintf(&b, "<div class=\"body\">%s</div>\n", esc(strings.TrimSpace(c.Body)))
+			walk(c.Replies)
+			b.WriteString("</div>\n")
+		}
+	}
+	walk(comments)
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+// ============= Export Action =============
+
+type exportDoneMsg struct {
+	path    string
+	missing int
+	error   error
+}
+
+// exportSelected fetches the selected post's full comment tree and writes it
+// in the configured export format.
+func (m Model) exportSelected() tea.Cmd {
+	if len(m.filteredPosts) == 0 || m.list.Index() >= len(m.filteredPosts) {
+		return nil
+	}
+	post := m.filteredPosts[m.list.Index()]
+	subreddit := post.SubName
+	if subreddit == "" {
+		subreddit = m.subreddit
+	}
+	sort, format, dir := m.commentSort, appConfig.TUI.ExportFormat, exportDir()
+	return func() tea.Msg {
+		full, comments, missing, err := m.client.FetchWholeThread(subreddit, post.ID, sort)
+		if err != nil {
+			return exportDoneMsg{"", 0, err}
+		}
+		path, err := exportThread(full, comments, missing, format, dir, false)
+		return exportDoneMsg{path, missing, err}
+	}
+}
+
+// ============= Export Command =============
+
+func runExport(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", appConfig.TUI.ExportFormat, "markdown, html or json")
+	dir := fs.String("dir", exportDir(), "directory to write to")
+	sort := fs.String("sort", appConfig.TUI.CommentSort, "best, top, new, controversial, old or qa")
+	force := fs.Bool("force", false, "replace an earlier export of the post")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usageError{fmt.Errorf("expected one post link or ID")}
+	}
+	if !validExportFormat(*format) {
+		return usageError{fmt.Errorf("unknown format %q (want markdown, html or json)", *format)}
+	}
+	if !validCommentSort(*sort) {
+		return usageError{fmt.Errorf("unknown sort %q", *sort)}
+	}
+	ref, err := parsePostRef(positional[0])
+	if err != nil {
+		return usageError{err}
+	}
+
+	post, comments, missing, err := NewAPIClient().FetchWholeThread(ref.subreddit, ref.id, *sort)
+	if err != nil {
+		return err
+	}
+	path, err := exportThread(post, comments, missing, *format, *dir, *force)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, path)
+	return err
+}
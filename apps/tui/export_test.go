@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		post RedditPostData
+		want string
+	}{
+		{"title", RedditPostData{ID: "1c2x3yz", SubName: "golang", Title: "Go 1.23: What's New?"}, "golang-1c2x3yz-go-1-23-what-s-new.md"},
+		{"title in another script", RedditPostData{ID: "1c2x3yz", SubName: "golang", Title: "日本語のタイトル"}, "golang-1c2x3yz.md"},
+		{"title of symbols", RedditPostData{ID: "1c2x3yz", SubName: "golang", Title: "?!"}, "golang-1c2x3yz.md"},
+		{"no subreddit", RedditPostData{ID: "1c2x3yz", Title: "Über"}, "1c2x3yz-ber.md"},
+		{"nothing usable", RedditPostData{Title: "…"}, "post.md"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exportFilename(tt.post, "markdown"); got != tt.want {
+				t.Errorf("exportFilename(%q) = %q, want %q", tt.post.Title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportKeepsEarlierFiles(t *testing.T) {
+	dir := t.TempDir()
+	post := RedditPostData{ID: "1c2x3yz", SubName: "golang", Title: "Hello"}
+	export := func(title string, force bool) string {
+		t.Helper()
+		post.SelfText = title
+		path, err := exportThread(post, nil, 0, "json", dir, force)
+		if err != nil {
+			t.Fatalf("exportThread: %v", err)
+		}
+		return filepath.Base(path)
+	}
+
+	for _, want := range []string{"golang-1c2x3yz-hello.json", "golang-1c2x3yz-hello-2.json", "golang-1c2x3yz-hello-3.json"} {
+		if got := export(want, false); got != want {
+			t.Errorf("export wrote %s, want %s", got, want)
+		}
+	}
+	if got := export("forced", true); got != "golang-1c2x3yz-hello.json" {
+		t.Errorf("forced export wrote %s, want the first name", got)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("%d files in the export directory, want 3", len(entries))
+	}
+	first, err := os.ReadFile(filepath.Join(dir, "golang-1c2x3yz-hello.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(first), `"selftext": "forced"`) {
+		t.Errorf("forced export did not replace the first file:\n%s", first)
+	}
+	second, _ := os.ReadFile(filepath.Join(dir, "golang-1c2x3yz-hello-2.json"))
+	if !strings.Contains(string(second), `"selftext": "golang-1c2x3yz-hello-2.json"`) {
+		t.Errorf("numbered export was overwritten:\n%s", second)
+	}
+}
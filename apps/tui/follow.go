@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ============= Follow Mode =============
+
+// Following an open post fetches its comments again every follow_seconds
+// and merges them into the tree on screen: comments already shown keep
+// their place and get their new scores and edits, and new ones are added
+// after their siblings and marked ✨. The comment at the top of the panel
+// stays there, so reading is not interrupted. Following stops when the
+// post is closed or another post is opened.
+//
+// The comments panel loads a post's first few threads to a limited depth,
+// so follow mode fetches the whole thread instead. The first fetch after
+// the comments load only fills in what the panel left out; nothing it adds
+// is counted as new.
+
+// minFollowSeconds keeps follow mode well inside Reddit's rate limits.
+const minFollowSeconds = 10
+
+// followTickMsg carries the follow session it was scheduled for, so ticks
+// from a session that has been stopped are dropped.
+type followTickMsg struct {
+	session int
+}
+
+type followedMsg struct {
+	postID   string
+	sort     string
+	seed     bool
+	comments []*Comment
+	error    error
+}
+
+func scheduleFollow(session int) tea.Cmd {
+	interval := time.Duration(appConfig.TUI.FollowSeconds) * time.Second
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return followTickMsg{session}
+	})
+}
+
+// toggleFollow starts following the open post, opening its comments if
+// neither they nor the reader are showing, or stops following it.
+func (m *Model) toggleFollow() tea.Cmd {
+	if m.following != "" {
+		m.stopFollowing()
+		return m.showToast("👁 Stopped following")
+	}
+	if !m.showDetails || m.list.Index() >= len(m.filteredPosts) {
+		return m.showToast("👁 Open a post to follow its comments")
+	}
+	if !m.showComments && !m.reading {
+		m.showComments = true
+	}
+	sync := m.syncComments()
+	m.following = m.filteredPosts[m.list.Index()].ID
+	m.followSession++
+	m.followSeeded = false
+	text := fmt.Sprintf("👁 Following comments, every %ds", appConfig.TUI.FollowSeconds)
+	cmds := []tea.Cmd{sync, m.showToast(text), scheduleFollow(m.followSession)}
+	if sync == nil && !m.commentsLoading {
+		cmds = append(cmds, m.fetchFollowed())
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) stopFollowing() {
+	m.following = ""
+	m.followSeeded = false
+	m.newComments = nil
+}
+
+// followTick fetches the followed post's comments and schedules the next
+// tick, or stops following when the post is no longer open.
+func (m *Model) followTick(msg followTickMsg) tea.Cmd {
+	if msg.session != m.followSession || m.following == "" {
+		return nil
+	}
+	if !m.showDetails || m.commentsPostID != m.following {
+		m.stopFollowing()
+		return nil
+	}
+	next := scheduleFollow(m.followSession)
+	if m.commentsLoading || m.followFetching {
+		return next
+	}
+	return tea.Batch(next, m.fetchFollowed())
+}
+
+// fetchFollowed fetches the followed post's whole comment thread.
+func (m *Model) fetchFollowed() tea.Cmd {
+	m.followFetching = true
+	subreddit := m.subreddit
+	if i := m.list.Index(); i < len(m.filteredPosts) && m.filteredPosts[i].SubName != "" {
+		subreddit = m.filteredPosts[i].SubName
+	}
+	postID, sort, seed, client := m.following, m.commentSort, !m.followSeeded, m.client
+	return func() tea.Msg {
+		_, comments, err := client.FetchThread(subreddit, postID, sort)
+		return followedMsg{postID, sort, seed, comments, err}
+	}
+}
+
+// applyFollowed merges a fetch into the open comments, keeping the comment
+// at the top of the panel or reader in place.
+func (m *Model) applyFollowed(msg followedMsg) tea.Cmd {
+	m.followFetching = false
+	if msg.postID != m.following || msg.postID != m.commentsPostID || msg.sort != m.commentSort || m.commentsLoading {
+		return nil
+	}
+	if msg.error != nil {
+		return m.showToast("⚠ Follow refresh failed: " + msg.error.Error())
+	}
+
+	anchor, offset := m.commentAnchor()
+	fresh := m.newComments
+	if msg.seed {
+		// Comments the panel left out are not new
+		fresh = nil
+	} else if fresh == nil {
+		fresh = make(map[string]bool)
+		m.newComments = fresh
+	}
+	var added int
+	m.comments, added = mergeComments(m.comments, msg.comments, fresh)
+	m.relayout()
+	m.restoreCommentAnchor(anchor, offset)
+	if msg.seed {
+		m.followSeeded = true
+		return nil
+	}
+	if added == 0 {
+		return nil
+	}
+	m.addCommentCount(msg.postID, added)
+	if added == 1 {
+		return m.showToast("✨ 1 new comment")
+	}
+	return m.showToast(fmt.Sprintf("✨ %d new comments", added))
+}
+
+// mergeComments updates the comments in current from fetched, matching
+// them by ID at each level. Comments not in current are appended after
+// their siblings and recorded in fresh, when it is not nil, with their
+// replies. It returns the merged comments and how many were added.
+func mergeComments(current, fetched []*Comment, fresh map[string]bool) ([]*Comment, int) {
+	byID := make(map[string]*Comment, len(current))
+	for _, c := range current {
+		byID[c.ID] = c
+	}
+	added := 0
+	for _, f := range fetched {
+		c, ok := byID[f.ID]
+		if !ok {
+			current = append(current, f)
+			added += markFresh(f, fresh)
+			continue
+		}
+		c.Score = f.Score
+		c.Body = f.Body
+		c.Likes = f.Likes
+		var n int
+		c.Replies, n = mergeComments(c.Replies, f.Replies, fresh)
+		added += n
+	}
+	return current, added
+}
+
+// markFresh records c and its replies as new, returning how many there are.
+func markFresh(c *Comment, fresh map[string]bool) int {
+	if fresh != nil {
+		fresh[c.ID] = true
+	}
+	n := 1
+	for _, r := range c.Replies {
+		n += markFresh(r, fresh)
+	}
+	return n
+}
+
+// commentAnchor returns the comment at the top of the comments panel or
+// reader, and how many lines into it the view starts. The ID is empty when
+// the view is above the comments.
+func (m Model) commentAnchor() (string, int) {
+	_, starts := m.commentLines()
+	flat := m.flatComments()
+	top := m.commentsScrollY
+	if m.reading {
+		_, commentsAt := m.readerLines()
+		top = m.reader.YOffset - commentsAt - 2
+	}
+	id, offset := "", 0
+	for i, start := range starts {
+		if start <= top {
+			id, offset = flat[i].ID, top-start
+		}
+	}
+	return id, offset
+}
+
+// restoreCommentAnchor scrolls the comment commentAnchor returned back to
+// the top of the view.
+func (m *Model) restoreCommentAnchor(id string, offset int) {
+	if id == "" {
+		return
+	}
+	_, starts := m.commentLines()
+	for i, c := range m.flatComments() {
+		if c.ID != id {
+			continue
+		}
+		if m.reading {
+			_, commentsAt := m.readerLines()
+			m.reader.SetYOffset(commentsAt + 2 + starts[i] + offset)
+		} else {
+			m.commentsScrollY = min(starts[i]+offset, m.commentsMaxScroll)
+		}
+		return
+	}
+}
+
+// addCommentCount raises a post's comment count by n in the list.
+func (m *Model) addCommentCount(postID string, n int) {
+	// posts and filteredPosts may share an array, so set rather than add
+	count := -1
+	for _, post := range m.filteredPosts {
+		if post.ID == postID {
+			count = post.Comments + n
+		}
+	}
+	if count < 0 {
+		return
+	}
+	for i := range m.posts {
+		if m.posts[i].ID == postID {
+			m.posts[i].Comments = count
+		}
+	}
+	for i := range m.filteredPosts {
+		if m.filteredPosts[i].ID == postID {
+			m.filteredPosts[i].Comments = count
+			m.list.SetItem(i, m.postItem(m.filteredPosts[i]))
+		}
+	}
+}
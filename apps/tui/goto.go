@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ============= Go To Post =============
+
+// Post IDs are short base-36 strings, e.g. "1c2x3yz".
+var postIDPattern = regexp.MustCompile(`^[a-z0-9]{4,12}$`)
+
+// postRef names a post by ID, and by subreddit when the link included one.
+type postRef struct {
+	subreddit string
+	id        string
+}
+
+// parsePostRef accepts a reddit.com permalink, a redd.it short link, a
+// "/r/<sub>/comments/<id>" path or a bare post ID, with or without "t3_".
+func parsePostRef(s string) (postRef, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return postRef{}, fmt.Errorf("no post link or ID given")
+	}
+	if id := strings.TrimPrefix(strings.ToLower(s), "t3_"); postIDPattern.MatchString(id) {
+		return postRef{id: id}, nil
+	}
+
+	raw := s
+	if !strings.Contains(raw, "://") && !strings.HasPrefix(raw, "/") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return postRef{}, fmt.Errorf("not a Reddit link or post ID: %q", s)
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	parts := strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+
+	var ref postRef
+	switch {
+	case host == "redd.it" && len(parts) > 0:
+		ref.id = parts[0]
+	case host == "" || host == "reddit.com" || strings.HasSuffix(host, ".reddit.com"):
+		for i := 0; i+1 < len(parts); i++ {
+			if parts[i] == "comments" {
+				ref.id = parts[i+1]
+				if i >= 2 && parts[i-2] == "r" {
+					ref.subreddit = parts[i-1]
+				}
+				break
+			}
+		}
+		if ref.id == "" && len(parts) >= 4 && parts[0] == "r" && parts[2] == "s" {
+			return postRef{}, fmt.Errorf("share links (/r/%s/s/...) must be opened in a browser to get the post link", parts[1])
+		}
+	}
+
+	ref.id = strings.ToLower(ref.id)
+	if !postIDPattern.MatchString(ref.id) {
+		return postRef{}, fmt.Errorf("not a Reddit link or post ID: %q", s)
+	}
+	return ref, nil
+}
+
+type postLoadedMsg struct {
+	post     RedditPostData
+	comments []*Comment
+	sort     string
+	focus    string // comment to scroll to, when opened from the inbox
+	error    error
+}
+
+func (m Model) fetchPost(ref postRef) tea.Cmd {
+	sort := m.commentSort
+	return func() tea.Msg {
+		post, comments, err := m.client.FetchPost(ref.subreddit, ref.id, sort)
+		return postLoadedMsg{post, comments, sort, "", err}
+	}
+}
+
+// handleGotoKey drives the "go to" prompt.
+func (m Model) handleGotoKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Cancel):
+		m.goingTo = false
+		m.gotoInput.Reset()
+		return m, nil
+	case key.Matches(msg, m.keys.Confirm):
+		ref, err := parsePostRef(m.gotoInput.Value())
+		if err != nil {
+			return m, m.showToast("⚠ " + err.Error())
+		}
+		m.goingTo = false
+		m.gotoInput.Reset()
+		return m, tea.Batch(m.showToast("🔗 Opening post "+ref.id+"..."), m.fetchPost(ref))
+	}
+	var cmd tea.Cmd
+	m.gotoInput, cmd = m.gotoInput.Update(msg)
+	return m, cmd
+}
+
+// openFetchedPost shows a post fetched by ID in the detail view, adding it to
+// the top of the listing when it is not already there.
+func (m *Model) openFetchedPost(msg postLoadedMsg) {
+	i := -1
+	for j, post := range m.filteredPosts {
+		if post.ID == msg.post.ID {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		m.posts = append([]RedditPostData{msg.post}, m.posts...)
+		m.filteredPosts = append([]RedditPostData{msg.post}, m.filteredPosts...)
+		m.updateListItems()
+		i = 0
+	}
+
+	m.list.Select(i)
+	m.detailScrollY = 0
+	m.commentsScrollY = 0
+	m.reader.YOffset = 0
+	m.match = noMatch
+	m.showDetails = true
+	m.showComments = false
+	m.reading = false
+
+	m.comments = msg.comments
+	m.commentsPostID = msg.post.ID
+	m.commentsLoading = false
+	if msg.sort != m.commentSort {
+		m.commentsPostID = "" // sort changed while loading; refetch when opened
+	}
+	if msg.focus != "" {
+		m.showComments = true
+	}
+	m.relayout()
+
+	// Scroll the comments panel to the comment, its parents just above
+	_, starts := m.commentLines()
+	for j, c := range m.flatComments() {
+		if msg.focus != "" && c.ID == msg.focus {
+			m.commentsScrollY = min(starts[j], m.commentsMaxScroll)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ============= Help Overlay =============
+
+// helpSection is one mode's bindings, described the way they behave there.
+type helpSection struct {
+	title    string
+	bindings []key.Binding
+}
+
+// as returns a copy of b whose help text reads desc.
+func as(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
+}
+
+// helpSections builds the overlay content from the live keymap, so remapped
+// and unbound keys are reflected as they are.
+func (m Model) helpSections() []helpSection {
+	k := m.keys
+	shortcuts := key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"), key.WithHelp("1-9", "subreddit shortcut"))
+	shortcuts.SetEnabled(len(m.shortcuts()) > 0)
+	count := key.NewBinding(key.WithKeys("1"), key.WithHelp("[count]", "repeat a motion, e.g. 5j"))
+
+	return []helpSection{
+		{"List", []key.Binding{
+			as(k.Up, "previous post"), as(k.Down, "next post"),
+			k.PageUp, k.PageDown, k.HalfPageUp, k.HalfPageDown,
+			as(k.Top, "first post ([count]: post n)"), as(k.Bottom, "last post"),
+			k.NextMatch, k.PrevMatch, count,
+			k.Open, as(k.Reader, "read post and comments"), as(k.Fullscreen, "open full screen"), k.OpenURL, as(k.Copy, "copy link/title"),
+			as(k.Export, "export thread to a file"),
+			k.Upvote, k.Downvote, k.ClearVote, as(k.Reply, "reply to post"), as(k.NewPost, "submit a new post"),
+			k.Search, as(k.Subreddit, "change subreddit (Tab completes)"), shortcuts, as(k.Home, "home feed"), k.GoTo, as(k.Inbox, "inbox"),
+			as(k.Alerts, "watch alerts"),
+			k.Refresh, k.ToggleSort, as(k.AbsoluteTime, "absolute times"),
+			k.Help, k.Quit,
+		}},
+		{"Details", []key.Binding{
+			as(k.Up, "scroll up"), as(k.Down, "scroll down"),
+			k.PageUp, k.PageDown, k.HalfPageUp, k.HalfPageDown, k.Top, k.Bottom,
+			k.NextMatch, k.PrevMatch, k.Center,
+			k.PrevPost, k.NextPost,
+			as(k.Comments, "show comments"), as(k.Reader, "read post and comments"), k.OpenURL, as(k.Copy, "copy link/title"),
+			k.Export, k.Upvote, k.Downvote, k.ClearVote, as(k.Reply, "reply to post"),
+			as(k.Follow, "follow comments live"),
+			k.Fullscreen, k.ShrinkList, k.GrowList,
+			as(k.Back, "back to list"),
+		}},
+		{"Comments", []key.Binding{
+			as(k.Up, "scroll up (previous post at top)"), as(k.Down, "scroll down (next post at end)"),
+			k.PageUp, k.PageDown, k.HalfPageUp, k.HalfPageDown, k.Top, k.Bottom,
+			k.NextMatch, k.PrevMatch, as(k.Center, "center comment/match"),
+			k.PrevPost, k.NextPost,
+			as(k.Copy, "copy (c: comment)"), k.Export, as(k.CommentSort, "cycle comment sort"),
+			as(k.Follow, "follow comments live"),
+			as(k.Upvote, "upvote comment"), as(k.Downvote, "downvote comment"), k.ClearVote,
+			as(k.Reply, "reply to comment"),
+			k.Fullscreen, k.ShrinkList, k.GrowList,
+			as(k.Comments, "close comments"), as(k.Back, "close comments"),
+		}},
+		{"Reader", []key.Binding{
+			as(k.Up, "scroll up"), as(k.Down, "scroll down"),
+			k.PageUp, k.PageDown, k.HalfPageUp, k.HalfPageDown, k.Top, k.Bottom,
+			as(k.JumpComments, "jump between body and comments"),
+			k.NextMatch, k.PrevMatch, k.Center,
+			k.PrevPost, k.NextPost,
+			k.OpenURL, as(k.Copy, "copy (c: comment)"), k.Export, as(k.CommentSort, "cycle comment sort"),
+			as(k.Follow, "follow comments live"),
+			as(k.Upvote, "upvote post or comment"), as(k.Downvote, "downvote post or comment"), k.ClearVote,
+			as(k.Reply, "reply to post or comment"),
+			k.Fullscreen, k.ShrinkList, k.GrowList,
+			as(k.Reader, "close reader"), as(k.Back, "close reader"),
+		}},
+		{"Inbox", []key.Binding{
+			as(k.Up, "previous item"), as(k.Down, "next item"), k.PageUp, k.PageDown, k.Top, k.Bottom,
+			as(k.PrevPost, "previous tab"), as(k.NextPost, "next tab"),
+			as(k.Open, "open thread at the comment (messages: mark read)"),
+			k.MarkRead, k.MarkAllRead, as(k.Refresh, "reload inbox"),
+			as(k.Inbox, "close inbox"), as(k.Back, "close inbox"),
+		}},
+		{"Watch alerts", []key.Binding{
+			as(k.Up, "previous alert"), as(k.Down, "next alert"), k.PageUp, k.PageDown, k.Top, k.Bottom,
+			as(k.Open, "open the post"),
+			as(k.Alerts, "close alerts"), as(k.Back, "close alerts"),
+		}},
+		{"Search", []key.Binding{
+			as(k.Confirm, "search all of Reddit"), as(k.Cancel, "cancel and clear filter"),
+		}},
+		{"Subreddit picker", []key.Binding{
+			as(k.Confirm, "load subreddit"), as(k.Cancel, "cancel"),
+		}},
+		{"Go to post", []key.Binding{
+			as(k.Confirm, "open link or ID"), as(k.Cancel, "cancel"),
+		}},
+		{"Reply composer", []key.Binding{
+			as(k.Submit, "send reply"), as(k.Preview, "toggle markdown preview"),
+			as(k.Editor, "edit in $EDITOR"), as(k.Cancel, "close, keeping the draft"),
+		}},
+		{"New post", []key.Binding{
+			as(k.Submit, "check rules and post"), k.NextField, k.PrevField,
+			as(k.Toggle, "toggle type, flair, NSFW or spoiler"), as(k.Confirm, "next field (toggle on options)"),
+			as(k.Cancel, "close, keeping the draft"),
+		}},
+	}
+}
+
+func newHelpModel() help.Model {
+	h := help.New()
+	keyStyle := lipgloss.NewStyle().Foreground(theme.Highlight).Bold(true)
+	descStyle := lipgloss.NewStyle().Foreground(theme.Text)
+	sepStyle := lipgloss.NewStyle().Foreground(theme.Surface)
+	h.Styles.ShortKey, h.Styles.FullKey = keyStyle, keyStyle
+	h.Styles.ShortDesc, h.Styles.FullDesc = descStyle, descStyle
+	h.Styles.ShortSeparator, h.Styles.FullSeparator = sepStyle, sepStyle
+	h.Styles.Ellipsis = sepStyle
+	h.FullSeparator = "    "
+	return h
+}
+
+// helpLines renders every section, one line per mode in short form or in
+// columns in full form.
+func (m Model) helpLines(width int) []string {
+	h := m.help
+	var lines []string
+	for _, section := range m.helpSections() {
+		var enabled []key.Binding
+		for _, b := range section.bindings {
+			if b.Enabled() {
+				enabled = append(enabled, b)
+			}
+		}
+		if len(enabled) == 0 {
+			continue
+		}
+
+		lines = append(lines, styles.Focused.Render(section.title))
+		if !m.helpFull {
+			h.Width = width
+			lines = append(lines, h.ShortHelpView(enabled))
+		} else {
+			lines = append(lines, strings.Split(fullHelpColumns(h, enabled, width), "\n")...)
+		}
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// fullHelpColumns lays bindings out in as many columns as fit in width.
+func fullHelpColumns(h help.Model, bindings []key.Binding, width int) string {
+	h.Width = 0
+	var out string
+	for rows := 6; ; rows++ {
+		var groups [][]key.Binding
+		for i := 0; i < len(bindings); i += rows {
+			groups = append(groups, bindings[i:min(i+rows, len(bindings))])
+		}
+		out = h.FullHelpView(groups)
+		if lipgloss.Width(out) <= width || len(groups) == 1 {
+			return out
+		}
+	}
+}
+
+// helpHeight is the number of help lines visible in the overlay box.
+func (m Model) helpHeight() int {
+	return max(1, m.windowHeight-6)
+}
+
+func (m Model) helpMaxScroll() int {
+	return max(0, len(m.helpLines(m.windowWidth-6))-m.helpHeight())
+}
+
+// handleHelpKey scrolls the overlay with the same motions as the panes,
+// counts and sequences such as "g g" included, and closes it.
+func (m Model) handleHelpKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	m, cmd, handled := m.handleMotion(msg)
+	if handled {
+		return m, cmd
+	}
+	k := m.keys
+	switch {
+	case msg.String() == "ctrl+c":
+		return m, tea.Quit
+	case key.Matches(msg, k.Back, k.Quit):
+		m.showHelp = false
+	case key.Matches(msg, k.Help):
+		m.helpFull = !m.helpFull
+		m.helpScrollY = 0
+	}
+	return m, nil
+}
+
+func (m Model) renderHelp() string {
+	width := m.windowWidth - 6
+	lines := m.helpLines(width)
+	height := m.helpHeight()
+
+	start := min(m.helpScrollY, max(0, len(lines)-height))
+	end := min(start+height, len(lines))
+	body := strings.Join(lines[start:end], "\n")
+
+	mode := "short"
+	if m.helpFull {
+		mode = "full"
+	}
+	title := styles.Header.Render("⌨  Keybindings")
+	footer := styles.Hint.Render(joinHints("  ",
+		keyHint("scroll", m.keys.Up, m.keys.Down),
+		keyHint("short/full ("+mode+")", m.keys.Help),
+		keyHint("close", m.keys.Back)))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Accent).
+		Padding(0, 1).
+		Width(m.windowWidth - 2).
+		Height(height).
+		Render(body)
+
+	return title + "\n" + box + "\n" + footer
+}
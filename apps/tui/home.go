@@ -0,0 +1,168 @@
+package main
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ============= Home Feed =============
+
+// Logged in, the home feed is the front page: the user's subscriptions, as
+// on reddit.com. The subscriptions also fill the subreddit picker's
+// completions and the 1-9 shortcuts. Anonymous users, or anyone with
+// home_feed set to "subreddit", start in default_subreddit and keep the
+// configured shortcuts.
+
+const (
+	homeFeedAuto      = "auto"
+	homeFeedSubreddit = "subreddit"
+)
+
+// frontPage is the subreddit value of the front page. It is not a valid
+// subreddit name, so it cannot be mistaken for one or for an unset value;
+// FetchPosts and commentsURL handle it when building URLs.
+const frontPage = "(front)"
+
+// maxSubscriptionPages caps how many pages of 100 subscriptions are read.
+const maxSubscriptionPages = 10
+
+func validHomeFeed(feed string) bool {
+	return feed == homeFeedAuto || feed == homeFeedSubreddit
+}
+
+// useSubscriptions reports whether the home feed and shortcuts come from the
+// account rather than the config.
+func useSubscriptions(c *APIClient) bool {
+	return c.Authenticated() && appConfig.TUI.HomeFeed == homeFeedAuto
+}
+
+// homeSubreddit is where the UI starts and where the home key goes.
+func homeSubreddit(c *APIClient) string {
+	if useSubscriptions(c) {
+		return frontPage
+	}
+	return appConfig.TUI.DefaultSubreddit
+}
+
+// feedName names a subreddit value for the header and loading messages.
+func feedName(subreddit string) string {
+	if subreddit == frontPage {
+		return "Front page"
+	}
+	return "r/" + subreddit
+}
+
+// Subscriptions lists the subreddits the user is subscribed to, sorted by
+// name.
+func (c *APIClient) Subscriptions() ([]string, error) {
+	var subs []string
+	after := ""
+	for page := 0; page < maxSubscriptionPages; page++ {
+		q := url.Values{"limit": {"100"}}
+		if after != "" {
+			q.Set("after", after)
+		}
+		var listing struct {
+			Data struct {
+				After    string `json:"after"`
+				Children []struct {
+					Data struct {
+						Name string `json:"display_name"`
+					} `json:"data"`
+				} `json:"children"`
+			} `json:"data"`
+		}
+		if err := c.getJSON("/subreddits/mine/subscriber?"+q.Encode(), &listing); err != nil {
+			return nil, err
+		}
+		for _, child := range listing.Data.Children {
+			if child.Data.Name != "" {
+				subs = append(subs, child.Data.Name)
+			}
+		}
+		after = listing.Data.After
+		if after == "" {
+			break
+		}
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		return strings.ToLower(subs[i]) < strings.ToLower(subs[j])
+	})
+	return subs, nil
+}
+
+type subscriptionsMsg struct {
+	subreddits []string
+	error      error
+}
+
+// loadSubscriptions fetches the user's subscriptions, or does nothing when
+// they are not used.
+func (m Model) loadSubscriptions() tea.Cmd {
+	if !useSubscriptions(m.client) {
+		return nil
+	}
+	client := m.client
+	return func() tea.Msg {
+		subs, err := client.Subscriptions()
+		return subscriptionsMsg{subs, err}
+	}
+}
+
+// setSubscriptions makes subs the picker's completions and shortcuts.
+func (m *Model) setSubscriptions(subs []string) {
+	m.subscriptions = subs
+	m.subredditInput.SetSuggestions(m.subredditChoices())
+}
+
+// subredditChoices are the picker's completions: the subscriptions, or the
+// default subreddit and configured shortcuts.
+func (m Model) subredditChoices() []string {
+	if len(m.subscriptions) > 0 {
+		return m.subscriptions
+	}
+	choices := []string{appConfig.TUI.DefaultSubreddit}
+	seen := map[string]bool{strings.ToLower(appConfig.TUI.DefaultSubreddit): true}
+	shortcuts := m.shortcuts()
+	keys := make([]string, 0, len(shortcuts))
+	for k := range shortcuts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if sub := shortcuts[k]; !seen[strings.ToLower(sub)] {
+			seen[strings.ToLower(sub)] = true
+			choices = append(choices, sub)
+		}
+	}
+	return choices
+}
+
+// shortcuts maps 1-9 to subreddits: the first nine subscriptions when they
+// are loaded, otherwise subreddit_shortcuts.
+func (m Model) shortcuts() map[string]string {
+	if len(m.subscriptions) == 0 {
+		return appConfig.TUI.SubredditShortcuts
+	}
+	shortcuts := make(map[string]string, 9)
+	for i, sub := range m.subscriptions {
+		if i == 9 {
+			break
+		}
+		shortcuts[strconv.Itoa(i+1)] = sub
+	}
+	return shortcuts
+}
+
+// goHome loads the home feed.
+func (m *Model) goHome() tea.Cmd {
+	m.subreddit = homeSubreddit(m.client)
+	m.startLoading()
+	m.searchInput.Reset()
+	m.searching = false
+	return m.loadPosts(m.subreddit, m.sort)
+}
@@ -0,0 +1,426 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ============= Inbox =============
+
+// The inbox lists comment replies, username mentions and private messages,
+// newest first, under tabs that narrow it to one kind. Opening a reply or
+// mention loads its thread with the parent comments above it and scrolls
+// the comments panel to it. Messages are only marked read when asked, not
+// by opening the inbox.
+
+// inboxItem is a comment reply, mention ("t1") or private message ("t4").
+type inboxItem struct {
+	Kind      string
+	Name      string  `json:"name"` // fullname, e.g. t1_abc
+	Type      string  `json:"type"` // comment_reply, post_reply, username_mention; unknown for messages
+	Author    string  `json:"author"`
+	Subject   string  `json:"subject"`
+	Body      string  `json:"body"`
+	Subreddit string  `json:"subreddit"`
+	LinkTitle string  `json:"link_title"`
+	Context   string  `json:"context"` // the comment's permalink with ?context=3
+	Created   float64 `json:"created_utc"`
+	New       bool    `json:"new"`
+}
+
+func (it inboxItem) mention() bool {
+	return it.Type == "username_mention"
+}
+
+func (it inboxItem) message() bool {
+	return it.Kind == "t4"
+}
+
+// inboxTabs are the views of the inbox, in the order h/l cycles them.
+var inboxTabs = []struct {
+	name string
+	show func(inboxItem) bool
+}{
+	{"All", func(inboxItem) bool { return true }},
+	{"Unread", func(it inboxItem) bool { return it.New }},
+	{"Replies", func(it inboxItem) bool { return !it.message() && !it.mention() }},
+	{"Mentions", inboxItem.mention},
+	{"Messages", inboxItem.message},
+}
+
+type inboxState struct {
+	items   []inboxItem
+	tab     int
+	cursor  int // index into visible()
+	loading bool
+	loaded  bool
+	err     string
+}
+
+// visible is the current tab's items.
+func (s inboxState) visible() []*inboxItem {
+	var items []*inboxItem
+	for i := range s.items {
+		if inboxTabs[s.tab].show(s.items[i]) {
+			items = append(items, &s.items[i])
+		}
+	}
+	return items
+}
+
+func (s inboxState) selected() *inboxItem {
+	items := s.visible()
+	if s.cursor < 0 || s.cursor >= len(items) {
+		return nil
+	}
+	return items[s.cursor]
+}
+
+func (s inboxState) unread() int {
+	n := 0
+	for _, it := range s.items {
+		if it.New {
+			n++
+		}
+	}
+	return n
+}
+
+// ============= Inbox API =============
+
+// maxInboxItems is how much of the inbox is fetched.
+const maxInboxItems = 100
+
+// Inbox fetches the newest replies, mentions and messages without marking
+// them read.
+func (c *APIClient) Inbox() ([]inboxItem, error) {
+	var listing struct {
+		Data struct {
+			Children []struct {
+				Kind string    `json:"kind"`
+				Data inboxItem `json:"data"`
+			} `json:"children"`
+		} `json:"data"`
+	}
+	q := url.Values{"limit": {fmt.Sprint(maxInboxItems)}, "mark": {"false"}}
+	if err := c.getJSON("/message/inbox?"+q.Encode(), &listing); err != nil {
+		return nil, err
+	}
+	items := make([]inboxItem, 0, len(listing.Data.Children))
+	for _, child := range listing.Data.Children {
+		it := child.Data
+		it.Kind = child.Kind
+		items = append(items, it)
+	}
+	return items, nil
+}
+
+// UnreadCount is the number of unread inbox items.
+func (c *APIClient) UnreadCount() (int, error) {
+	var me struct {
+		InboxCount int `json:"inbox_count"`
+	}
+	if err := c.getJSON("/api/v1/me", &me); err != nil {
+		return 0, err
+	}
+	return me.InboxCount, nil
+}
+
+// MarkRead marks inbox items read, or unread when read is false.
+func (c *APIClient) MarkRead(fullnames []string, read bool) error {
+	path := "/api/read_message"
+	if !read {
+		path = "/api/unread_message"
+	}
+	_, err := c.postForm(path, url.Values{"id": {strings.Join(fullnames, ",")}})
+	return err
+}
+
+// ============= Inbox Messages =============
+
+type inboxLoadedMsg struct {
+	items []inboxItem
+	error error
+}
+
+type unreadCountMsg struct {
+	count int
+	error error
+}
+
+type markReadMsg struct {
+	fullnames []string
+	read      bool
+	error     error
+}
+
+func (m Model) loadInbox() tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		items, err := client.Inbox()
+		return inboxLoadedMsg{items, err}
+	}
+}
+
+// loadUnreadCount fetches the header's unread count when logged in.
+func (m Model) loadUnreadCount() tea.Cmd {
+	if !m.client.Authenticated() {
+		return nil
+	}
+	client := m.client
+	return func() tea.Msg {
+		n, err := client.UnreadCount()
+		return unreadCountMsg{n, err}
+	}
+}
+
+// openInbox shows the inbox, loading it the first time.
+func (m *Model) openInbox() tea.Cmd {
+	if !m.client.Authenticated() {
+		return m.showToast("⚠ Log in to read your inbox (redditview login)")
+	}
+	m.showInbox = true
+	if m.inbox.loaded || m.inbox.loading {
+		return nil
+	}
+	m.inbox.loading = true
+	return m.loadInbox()
+}
+
+// setRead marks items read or unread in place and adjusts the unread count.
+func (m *Model) setRead(fullnames []string, read bool) {
+	for _, name := range fullnames {
+		for i := range m.inbox.items {
+			it := &m.inbox.items[i]
+			if it.Name == name && it.New == read {
+				it.New = !read
+				if read {
+					m.unread = max(0, m.unread-1)
+				} else {
+					m.unread++
+				}
+			}
+		}
+	}
+	// The unread tab may have lost the item under the cursor
+	m.inbox.cursor = max(0, min(m.inbox.cursor, len(m.inbox.visible())-1))
+}
+
+// markRead marks items read or unread at once and tells Reddit, undoing it
+// if the request fails.
+func (m *Model) markRead(fullnames []string, read bool) tea.Cmd {
+	if len(fullnames) == 0 {
+		return nil
+	}
+	m.setRead(fullnames, read)
+	client := m.client
+	return func() tea.Msg {
+		return markReadMsg{fullnames, read, client.MarkRead(fullnames, read)}
+	}
+}
+
+// openContext marks a reply or mention read and opens its thread at the
+// comment.
+func (m *Model) openContext(it inboxItem) tea.Cmd {
+	ref, err := parsePostRef(it.Context)
+	if err != nil || it.Context == "" {
+		return m.showToast("⚠ No thread to open for this item")
+	}
+	commentID := strings.TrimPrefix(it.Name, "t1_")
+	read := m.markRead(unreadNames(it), true)
+	m.showInbox = false
+
+	sort, client := m.commentSort, m.client
+	fetch := func() tea.Msg {
+		post, comments, err := client.FetchContext(ref.subreddit, ref.id, commentID)
+		return postLoadedMsg{post, comments, sort, commentID, err}
+	}
+	return tea.Batch(m.showToast("💬 Opening thread..."), fetch, read)
+}
+
+// unreadNames is the item's fullname if it is unread.
+func unreadNames(it inboxItem) []string {
+	if !it.New {
+		return nil
+	}
+	return []string{it.Name}
+}
+
+// handleInboxKey drives the inbox: moving, switching tabs, opening a
+// thread and marking read.
+func (m Model) handleInboxKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	k := m.keys
+	items := m.inbox.visible()
+	page := max(1, m.inboxRows()/inboxItemRows)
+	switch {
+	case key.Matches(msg, k.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, k.Inbox, k.Back):
+		m.showInbox = false
+	case key.Matches(msg, k.Up):
+		m.inbox.cursor = max(0, m.inbox.cursor-1)
+	case key.Matches(msg, k.Down):
+		m.inbox.cursor = max(0, min(len(items)-1, m.inbox.cursor+1))
+	case key.Matches(msg, k.PageUp, k.HalfPageUp):
+		m.inbox.cursor = max(0, m.inbox.cursor-page)
+	case key.Matches(msg, k.PageDown, k.HalfPageDown):
+		m.inbox.cursor = max(0, min(len(items)-1, m.inbox.cursor+page))
+	case key.Matches(msg, k.Top):
+		m.inbox.cursor = 0
+	case key.Matches(msg, k.Bottom):
+		m.inbox.cursor = max(0, len(items)-1)
+	case key.Matches(msg, k.PrevPost, k.NextPost):
+		step := 1
+		if key.Matches(msg, k.PrevPost) {
+			step = len(inboxTabs) - 1
+		}
+		m.inbox.tab = (m.inbox.tab + step) % len(inboxTabs)
+		m.inbox.cursor = 0
+	case key.Matches(msg, k.Refresh):
+		if !m.inbox.loading {
+			m.inbox.loading = true
+			return m, m.loadInbox()
+		}
+	case key.Matches(msg, k.MarkRead):
+		if it := m.inbox.selected(); it != nil {
+			return m, m.markRead([]string{it.Name}, !it.New)
+		}
+	case key.Matches(msg, k.MarkAllRead):
+		var names []string
+		for _, it := range m.inbox.items {
+			names = append(names, unreadNames(it)...)
+		}
+		return m, m.markRead(names, true)
+	case key.Matches(msg, k.Open):
+		it := m.inbox.selected()
+		if it == nil {
+			return m, nil
+		}
+		if it.message() {
+			// Messages are read in place; opening one marks it read
+			return m, m.markRead(unreadNames(*it), true)
+		}
+		return m, m.openContext(*it)
+	}
+	return m, nil
+}
+
+// ============= Inbox View =============
+
+// inboxItemRows is the height of an unselected item: header, snippet and a
+// blank line.
+const inboxItemRows = 3
+
+// inboxRows is the height of the content area the inbox fills.
+func (m Model) inboxRows() int {
+	return m.composerRows()
+}
+
+func (m Model) inboxTabBar() string {
+	var tabs []string
+	for i, tab := range inboxTabs {
+		label := tab.name
+		if tab.name == "Unread" && m.inbox.unread() > 0 {
+			label = fmt.Sprintf("%s (%d)", label, m.inbox.unread())
+		}
+		if i == m.inbox.tab {
+			tabs = append(tabs, styles.Focused.Render("["+label+"]"))
+		} else {
+			tabs = append(tabs, styles.Meta.Render(" "+label+" "))
+		}
+	}
+	return "📥 Inbox  " + strings.Join(tabs, " ")
+}
+
+// inboxItemLines renders one item: an unread dot, what it is and who sent
+// it, then the subject or thread and the text, in full when selected.
+func (m Model) inboxItemLines(it *inboxItem, selected bool, width int) []string {
+	dot := "  "
+	if it.New {
+		dot = styles.Warning.Render("● ")
+	}
+	what := "💬 Reply"
+	switch {
+	case it.message():
+		what = "✉ Message"
+	case it.mention():
+		what = "📣 Mention"
+	case it.Type == "post_reply":
+		what = "💬 Post reply"
+	}
+	header := fmt.Sprintf("%s from u/%s", what, it.Author)
+	if it.Subreddit != "" {
+		header += " in r/" + it.Subreddit
+	}
+	if age := formatTimestamp(it.Created, m.absoluteTime); age != "" {
+		header += "  •  " + age
+	}
+	style := styles.Meta
+	if selected {
+		style = styles.Focused
+	}
+	lines := []string{dot + style.Render(truncateTitle(header, width-2))}
+
+	about := it.Subject
+	if !it.message() && it.LinkTitle != "" {
+		about = "on: " + it.LinkTitle
+	}
+	if about != "" {
+		lines = append(lines, "  "+styles.Meta.Render(truncateTitle(about, width-2)))
+	}
+
+	body := strings.TrimSpace(it.Body)
+	if selected {
+		for _, l := range renderMarkdown(body, max(10, width-4)) {
+			lines = append(lines, "  │ "+l)
+		}
+	} else if body != "" {
+		snippet := strings.Join(strings.Fields(body), " ")
+		lines = append(lines, "  "+truncateTitle(snippet, width-2))
+	}
+	return append(lines, "")
+}
+
+func (m Model) renderInbox() string {
+	width := max(20, m.windowWidth-2)
+	rows := m.inboxRows()
+	var lines []string
+	items := m.inbox.visible()
+	switch {
+	case m.inbox.loading && !m.inbox.loaded:
+		lines = append(lines, styles.Meta.Render(m.spinner.View()+" Loading inbox..."))
+	case m.inbox.err != "" && !m.inbox.loaded:
+		lines = append(lines, styles.Warning.Render("⚠ "+m.inbox.err))
+	case len(items) == 0:
+		lines = append(lines, styles.Meta.Render("Nothing here"))
+	default:
+		// Lay out every item, then scroll so the selected one is in view
+		var body []string
+		selStart, selEnd := 0, 0
+		for i, it := range items {
+			if i == m.inbox.cursor {
+				selStart = len(body)
+			}
+			body = append(body, m.inboxItemLines(it, i == m.inbox.cursor, width)...)
+			if i == m.inbox.cursor {
+				selEnd = len(body)
+			}
+		}
+		top := 0
+		if selEnd > rows {
+			top = min(selStart, selEnd-rows)
+		}
+		end := min(len(body), top+rows)
+		lines = append(lines, body[top:end]...)
+	}
+
+	return styles.Body.
+		Padding(0, 1).
+		Height(rows).
+		MaxHeight(rows).
+		Render(strings.Join(lines, "\n"))
+}
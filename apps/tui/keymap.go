@@ -0,0 +1,405 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// ============= Key Bindings =============
+
+// KeyMap holds every remappable binding. The config.json "keybindings"
+// section maps the action names below to lists of keys.
+type KeyMap struct {
+	// Motions (see motion.go); these accept counts and key sequences
+	Up           key.Binding
+	Down         key.Binding
+	PrevPost     key.Binding
+	NextPost     key.Binding
+	PageUp       key.Binding
+	PageDown     key.Binding
+	HalfPageUp   key.Binding
+	HalfPageDown key.Binding
+	Top          key.Binding
+	Bottom       key.Binding
+	Center       key.Binding
+	NextMatch    key.Binding
+	PrevMatch    key.Binding
+
+	// Actions
+	Open         key.Binding
+	Back         key.Binding
+	Comments     key.Binding
+	Reader       key.Binding
+	JumpComments key.Binding
+	CommentSort  key.Binding
+	OpenURL      key.Binding
+	Copy         key.Binding
+	Export       key.Binding
+	Follow       key.Binding
+	Reply        key.Binding
+	NewPost      key.Binding
+	Upvote       key.Binding
+	Downvote     key.Binding
+	ClearVote    key.Binding
+	Search       key.Binding
+	Subreddit    key.Binding
+	Home         key.Binding
+	Inbox        key.Binding
+	MarkRead     key.Binding
+	MarkAllRead  key.Binding
+	Alerts       key.Binding
+	GoTo         key.Binding
+	Refresh      key.Binding
+	ToggleSort   key.Binding
+	AbsoluteTime key.Binding
+	Fullscreen   key.Binding
+	ShrinkList   key.Binding
+	GrowList     key.Binding
+	Help         key.Binding
+	Quit         key.Binding
+
+	// Prompts (search and subreddit input), the reply composer and the
+	// post form
+	Confirm   key.Binding
+	Cancel    key.Binding
+	Submit    key.Binding
+	Preview   key.Binding
+	Editor    key.Binding
+	NextField key.Binding
+	PrevField key.Binding
+	Toggle    key.Binding
+}
+
+// keyScope says where a binding is live; bindings only conflict when their
+// scopes overlap.
+type keyScope int
+
+const (
+	scopeBrowse keyScope = 1 << iota // list, details and comments
+	scopePrompt                      // text input prompts, the composer and the post form
+)
+
+type keyAction struct {
+	name    string
+	desc    string
+	scope   keyScope
+	binding func(*KeyMap) *key.Binding
+	motion  bool // handled by the motion layer, so may be a key sequence
+}
+
+// keyActions lists the bindings in the order help text presents them.
+var keyActions = []keyAction{
+	{"up", "up", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Up }, true},
+	{"down", "down", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Down }, true},
+	{"prev_post", "previous post", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.PrevPost }, true},
+	{"next_post", "next post", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.NextPost }, true},
+	{"page_up", "page up", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.PageUp }, true},
+	{"page_down", "page down", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.PageDown }, true},
+	{"half_page_up", "half page up", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.HalfPageUp }, true},
+	{"half_page_down", "half page down", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.HalfPageDown }, true},
+	{"top", "go to top", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Top }, true},
+	{"bottom", "go to bottom", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Bottom }, true},
+	{"center", "center match", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Center }, true},
+	{"next_match", "next match", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.NextMatch }, true},
+	{"prev_match", "previous match", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.PrevMatch }, true},
+	{"open", "view post", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Open }, false},
+	{"back", "back", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Back }, false},
+	{"comments", "comments", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Comments }, false},
+	{"reader", "reader", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Reader }, false},
+	{"jump_comments", "body/comments", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.JumpComments }, false},
+	{"comment_sort", "comment sort", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.CommentSort }, false},
+	{"open_url", "open URL", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.OpenURL }, false},
+	{"copy", "copy", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Copy }, false},
+	{"export", "export thread", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Export }, false},
+	{"follow", "follow comments", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Follow }, false},
+	{"reply", "reply", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Reply }, false},
+	{"new_post", "new post", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.NewPost }, false},
+	{"upvote", "upvote", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Upvote }, false},
+	{"downvote", "downvote", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Downvote }, false},
+	{"clear_vote", "clear vote", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.ClearVote }, false},
+	{"search", "search", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Search }, false},
+	{"subreddit", "subreddit", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Subreddit }, false},
+	{"home", "home feed", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Home }, false},
+	{"inbox", "inbox", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Inbox }, false},
+	{"mark_read", "mark read/unread", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.MarkRead }, false},
+	{"mark_all_read", "mark all read", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.MarkAllRead }, false},
+	{"alerts", "watch alerts", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Alerts }, false},
+	{"goto", "go to post", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.GoTo }, false},
+	{"refresh", "refresh", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Refresh }, false},
+	{"toggle_sort", "toggle sort", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.ToggleSort }, false},
+	{"absolute_time", "absolute times", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.AbsoluteTime }, false},
+	{"fullscreen", "full-screen reader", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Fullscreen }, false},
+	{"shrink_list", "shrink list", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.ShrinkList }, false},
+	{"grow_list", "grow list", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.GrowList }, false},
+	{"help", "help", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Help }, false},
+	{"quit", "quit", scopeBrowse, func(k *KeyMap) *key.Binding { return &k.Quit }, false},
+	{"confirm", "confirm", scopePrompt, func(k *KeyMap) *key.Binding { return &k.Confirm }, false},
+	{"cancel", "cancel", scopePrompt, func(k *KeyMap) *key.Binding { return &k.Cancel }, false},
+	{"submit", "send", scopePrompt, func(k *KeyMap) *key.Binding { return &k.Submit }, false},
+	{"preview", "preview", scopePrompt, func(k *KeyMap) *key.Binding { return &k.Preview }, false},
+	{"editor", "open in $EDITOR", scopePrompt, func(k *KeyMap) *key.Binding { return &k.Editor }, false},
+	{"next_field", "next field", scopePrompt, func(k *KeyMap) *key.Binding { return &k.NextField }, false},
+	{"prev_field", "previous field", scopePrompt, func(k *KeyMap) *key.Binding { return &k.PrevField }, false},
+	{"toggle", "toggle", scopePrompt, func(k *KeyMap) *key.Binding { return &k.Toggle }, false},
+}
+
+var defaultKeys = map[string][]string{
+	"up":             {"up", "k"},
+	"down":           {"down", "j"},
+	"prev_post":      {"h", "left"},
+	"next_post":      {"l", "right"},
+	"page_up":        {"pgup", "b"},
+	"page_down":      {"pgdown", "f"},
+	"half_page_up":   {"ctrl+u"},
+	"half_page_down": {"ctrl+d"},
+	"top":            {"home", "g g"},
+	"bottom":         {"end", "G"},
+	"center":         {"z z"},
+	"next_match":     {"n"},
+	"prev_match":     {"N"},
+	"open":           {"enter"},
+	"back":           {"esc", "tab"},
+	"comments":       {"c"},
+	"reader":         {"r"},
+	"jump_comments":  {"C"},
+	"comment_sort":   {"o"},
+	"open_url":       {"w"},
+	"copy":           {"y"},
+	"export":         {"e"},
+	"follow":         {"F"},
+	"reply":          {"R"},
+	"new_post":       {"P"},
+	"upvote":         {"u"},
+	"downvote":       {"d"},
+	"clear_vote":     {"x"},
+	"search":         {"ctrl+f"},
+	"subreddit":      {"ctrl+r"},
+	"home":           {"H"},
+	"inbox":          {"i"},
+	"mark_read":      {"m"},
+	"mark_all_read":  {"M"},
+	"alerts":         {"A"},
+	"goto":           {"ctrl+g"},
+	"refresh":        {"f5"},
+	"toggle_sort":    {"t"},
+	"absolute_time":  {"a"},
+	"fullscreen":     {"v"},
+	"shrink_list":    {"<"},
+	"grow_list":      {">"},
+	"help":           {"?"},
+	"quit":           {"q", "ctrl+c"},
+	"confirm":        {"enter"},
+	"cancel":         {"esc"},
+	"submit":         {"ctrl+s"},
+	"preview":        {"ctrl+p"},
+	"editor":         {"ctrl+o"},
+	"next_field":     {"tab"},
+	"prev_field":     {"shift+tab"},
+	"toggle":         {" "},
+}
+
+// DefaultKeyMap returns the built-in bindings.
+func DefaultKeyMap() KeyMap {
+	km, _ := newKeyMap(nil)
+	return km
+}
+
+// newKeyMap builds a keymap from the defaults with overrides applied. An
+// empty key list unbinds an action, and motions accept space-separated key
+// sequences such as "g g". Unknown action names, sequences on other actions
+// and keys bound to two actions in the same scope are reported as an error.
+func newKeyMap(overrides map[string][]string) (KeyMap, error) {
+	var km KeyMap
+	var problems []string
+
+	known := make(map[string]bool, len(keyActions))
+	for _, action := range keyActions {
+		known[action.name] = true
+		keys := defaultKeys[action.name]
+		if custom, ok := overrides[action.name]; ok {
+			keys = custom
+		}
+		*action.binding(&km) = key.NewBinding(
+			key.WithKeys(keys...),
+			key.WithHelp(keyLabel(keys), action.desc),
+		)
+		if len(keys) == 0 {
+			action.binding(&km).SetEnabled(false)
+		}
+		for _, k := range keys {
+			if len(keySequence(k)) > 1 && !action.motion {
+				problems = append(problems, fmt.Sprintf("%s does not support key sequences (%q)", action.name, k))
+			}
+		}
+	}
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !known[name] {
+			problems = append(problems, fmt.Sprintf("unknown action %q", name))
+		}
+	}
+
+	problems = append(problems, km.conflicts()...)
+	if len(problems) > 0 {
+		return km, fmt.Errorf("keybindings: %s", strings.Join(problems, "; "))
+	}
+	return km, nil
+}
+
+// conflicts reports keys bound to more than one action in the same scope,
+// including the 1-9 subreddit shortcuts, and sequences whose first key is
+// already bound on its own.
+func (km KeyMap) conflicts() []string {
+	owners := make(map[string]string)
+	var problems []string
+
+	claim := func(k, owner string, scope keyScope) {
+		id := fmt.Sprintf("%d:%s", scope, k)
+		if prev, ok := owners[id]; ok {
+			problems = append(problems, fmt.Sprintf("%q is bound to both %s and %s", k, prev, owner))
+			return
+		}
+		owners[id] = owner
+	}
+
+	for _, action := range keyActions {
+		for _, k := range action.binding(&km).Keys() {
+			claim(k, action.name, action.scope)
+		}
+	}
+	shortcuts := make([]string, 0, len(appConfig.TUI.SubredditShortcuts))
+	for k := range appConfig.TUI.SubredditShortcuts {
+		shortcuts = append(shortcuts, k)
+	}
+	sort.Strings(shortcuts)
+	for _, k := range shortcuts {
+		claim(k, "subreddit shortcut", scopeBrowse)
+	}
+
+	for _, action := range keyActions {
+		for _, k := range action.binding(&km).Keys() {
+			seq := keySequence(k)
+			if len(seq) < 2 {
+				continue
+			}
+			if prev, ok := owners[fmt.Sprintf("%d:%s", action.scope, seq[0])]; ok {
+				problems = append(problems, fmt.Sprintf("%q of %s is shadowed by %q of %s", k, action.name, seq[0], prev))
+			}
+		}
+	}
+	return problems
+}
+
+// applyToList makes the list component navigate with our bindings instead
+// of its built-in ones.
+func (km KeyMap) applyToList(l *list.Model) {
+	l.KeyMap.CursorUp = km.Up
+	l.KeyMap.CursorDown = km.Down
+	l.KeyMap.PrevPage = km.PageUp
+	l.KeyMap.NextPage = km.PageDown
+	l.KeyMap.GoToStart = km.Top
+	l.KeyMap.GoToEnd = km.Bottom
+	l.KeyMap.Filter.SetEnabled(false)
+}
+
+var keyNames = map[string]string{
+	"up":        "↑",
+	"down":      "↓",
+	"left":      "←",
+	"right":     "→",
+	"enter":     "Enter",
+	"esc":       "Esc",
+	"tab":       "Tab",
+	"shift+tab": "Shift+Tab",
+	"pgup":      "PgUp",
+	"pgdown":    "PgDn",
+	"home":      "Home",
+	"end":       "End",
+	" ":         "Space",
+}
+
+// keyLabel renders keys the way the footer and docs spell them, e.g. "↑/k".
+func keyLabel(keys []string) string {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		labels[i] = keyName(k)
+	}
+	return strings.Join(labels, "/")
+}
+
+// keySequence splits a binding's key into the keys typed in turn; "g g" is
+// two presses of g.
+func keySequence(k string) []string {
+	if k == " " {
+		return []string{k}
+	}
+	return strings.Fields(k)
+}
+
+func keyName(k string) string {
+	if seq := keySequence(k); len(seq) > 1 {
+		for i, part := range seq {
+			seq[i] = keyName(part)
+		}
+		return strings.Join(seq, "")
+	}
+	if name, ok := keyNames[k]; ok {
+		return name
+	}
+	if strings.HasPrefix(k, "ctrl+") || strings.HasPrefix(k, "alt+") || (len(k) > 1 && k[0] == 'f') {
+		parts := strings.Split(k, "+")
+		for i, p := range parts {
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		}
+		return strings.Join(parts, "+")
+	}
+	return k
+}
+
+// primaryKey names the first key of a binding, or "" if it is unbound.
+func primaryKey(b key.Binding) string {
+	if keys := b.Keys(); len(keys) > 0 {
+		return keyName(keys[0])
+	}
+	return ""
+}
+
+// keyHint renders "keys: desc" for the footer. A single binding lists all of
+// its keys; several bindings show the first key of each, e.g. "h/l".
+func keyHint(desc string, bindings ...key.Binding) string {
+	var keys []string
+	for _, b := range bindings {
+		if !b.Enabled() || len(b.Keys()) == 0 {
+			continue
+		}
+		if len(bindings) == 1 {
+			keys = append(keys, b.Keys()...)
+		} else {
+			keys = append(keys, b.Keys()[0])
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	return keyLabel(keys) + ": " + desc
+}
+
+// joinHints joins non-empty footer hints with the footer separator.
+func joinHints(sep string, hints ...string) string {
+	var parts []string
+	for _, h := range hints {
+		if h != "" {
+			parts = append(parts, h)
+		}
+	}
+	return strings.Join(parts, sep)
+}
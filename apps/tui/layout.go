@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ============= Layout =============
+
+// All screen geometry is worked out here: the header and info bar take the
+// top two rows and the footer the last, and the rows in between hold the
+// post list, the separator and the reader panel.
+
+const (
+	contentTop = 2 // first row below the header and info bar
+	chromeRows = 3 // header, info bar and footer
+
+	minListRows  = 3
+	minPanelRows = 4
+	minListCols  = 30
+	minPanelCols = 40
+
+	defaultSplitRatio = 0.5
+	splitRatioStep    = 0.05
+)
+
+// Layout modes for the "layout" config option.
+const (
+	layoutAuto       = "auto"
+	layoutSideBySide = "side-by-side"
+	layoutStacked    = "stacked"
+)
+
+type rect struct {
+	x, y, w, h int
+}
+
+func (r rect) contains(x, y int) bool {
+	return x >= r.x && x < r.x+r.w && y >= r.y && y < r.y+r.h
+}
+
+// screenLayout places the list, separator and reader panel. Parts that are
+// not shown have an empty rect.
+type screenLayout struct {
+	list       rect
+	separator  rect
+	panel      rect
+	sideBySide bool
+}
+
+// computeLayout lays out a width×height screen. With no reader open the list
+// fills the content area; in full-screen mode the reader does. Otherwise the
+// list takes ratio of the content, beside the reader when mode is
+// side-by-side (or auto on a terminal at least sideBySideWidth wide) and
+// above it when stacked.
+func computeLayout(width, height int, reader, fullscreen bool, mode string, ratio float64, sideBySideWidth int) screenLayout {
+	top := contentTop
+	rows := max(1, height-chromeRows)
+
+	if !reader {
+		return screenLayout{list: rect{0, top, max(1, width-2), max(minListRows, rows)}}
+	}
+	if fullscreen {
+		return screenLayout{panel: rect{0, top, width, rows}}
+	}
+
+	ratio = clampRatio(ratio)
+	if mode == layoutSideBySide || (mode != layoutStacked && width >= sideBySideWidth) {
+		listCols := clampSplit(int(math.Round(float64(width)*ratio)), minListCols, width-1-minPanelCols)
+		return screenLayout{
+			list:       rect{0, top, listCols, rows},
+			separator:  rect{listCols, top, 1, rows},
+			panel:      rect{listCols + 1, top, max(1, width-listCols-1), rows},
+			sideBySide: true,
+		}
+	}
+
+	listRows := clampSplit(int(math.Round(float64(rows)*ratio)), minListRows, rows-1-minPanelRows)
+	return screenLayout{
+		list:      rect{0, top, max(1, width-2), listRows},
+		separator: rect{0, top + listRows, width, 1},
+		panel:     rect{0, top + listRows + 1, width, max(1, rows-listRows-1)},
+	}
+}
+
+// clampSplit keeps n within [lo, hi], preferring lo when the screen is too
+// small for both minimums.
+func clampSplit(n, lo, hi int) int {
+	return max(lo, min(n, hi))
+}
+
+func clampRatio(r float64) float64 {
+	if r <= 0 {
+		return defaultSplitRatio
+	}
+	return math.Max(0.1, math.Min(r, 0.9))
+}
+
+// layout is the model's current screen layout.
+func (m Model) layout() screenLayout {
+	reader := m.showDetails && len(m.filteredPosts) > 0
+	return computeLayout(m.windowWidth, m.windowHeight, reader, m.fullscreen,
+		appConfig.TUI.Layout, m.splitRatio, appConfig.TUI.SideBySideWidth)
+}
+
+// panelWidth is the width of the reader panel, or of the screen when no
+// reader is open.
+func (m Model) panelWidth() int {
+	if w := m.layout().panel.w; w > 0 {
+		return w
+	}
+	return m.windowWidth
+}
+
+// setSplitRatio changes the list's share of the split view.
+func (m *Model) setSplitRatio(r float64) {
+	m.splitRatio = clampRatio(r)
+	m.relayout()
+}
+
+// relayout resizes the list after the layout changed, keeping the panel
+// scroll positions within the new bounds.
+func (m *Model) relayout() {
+	m.updateListSize()
+	*m = m.calculateCommentsMaxScroll(m.detailsHeight())
+	m.commentsScrollY = min(m.commentsScrollY, m.commentsMaxScroll)
+	m.detailScrollY = min(m.detailScrollY, m.detailMaxScroll())
+	if m.reading {
+		m.reader = m.readerView()
+	}
+}
+
+// saveSplit persists the current split ratio, reporting the new split in
+// the info bar.
+func (m *Model) saveSplit() tea.Cmd {
+	appConfig.TUI.SplitRatio = m.splitRatio
+	if err := saveSplitRatio(m.splitRatio); err != nil {
+		return m.showToast(fmt.Sprintf("Could not save split ratio: %v", err))
+	}
+	return m.showToast(fmt.Sprintf("List %.0f%% of the screen", m.splitRatio*100))
+}
+
+// uiState is what the TUI remembers between runs on its own, kept in
+// state.json next to the login token so config.json is never rewritten.
+type uiState struct {
+	SplitRatio float64 `json:"split_ratio,omitempty"`
+}
+
+// statePath is redditview/state.json in the user's config directory.
+func statePath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "redditview", "state.json")
+}
+
+func loadState() uiState {
+	var st uiState
+	if data, err := os.ReadFile(statePath()); err == nil {
+		json.Unmarshal(data, &st)
+	}
+	return st
+}
+
+// applySavedSplit replaces the configured split ratio with the one last
+// set with the resize keys or the mouse, if any.
+func applySavedSplit() {
+	if r := loadState().SplitRatio; r > 0 {
+		appConfig.TUI.SplitRatio = clampRatio(r)
+	}
+}
+
+// saveSplitRatio stores the split ratio in the state file so it survives
+// restarts.
+func saveSplitRatio(r float64) error {
+	st := loadState()
+	st.SplitRatio = math.Round(r*100) / 100
+	path := statePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
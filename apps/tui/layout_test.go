@@ -0,0 +1,130 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestComputeLayout(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		reader        bool
+		fullscreen    bool
+		mode          string
+		ratio         float64
+		want          screenLayout
+	}{
+		{
+			name: "no reader", width: 100, height: 40, mode: layoutAuto, ratio: 0.5,
+			want: screenLayout{list: rect{0, 2, 98, 37}},
+		},
+		{
+			name: "full-screen reader", width: 100, height: 40, reader: true, fullscreen: true, mode: layoutAuto, ratio: 0.5,
+			want: screenLayout{panel: rect{0, 2, 100, 37}},
+		},
+		{
+			name: "wide terminal splits side by side", width: 160, height: 40, reader: true, mode: layoutAuto, ratio: 0.5,
+			want: screenLayout{
+				list:       rect{0, 2, 80, 37},
+				separator:  rect{80, 2, 1, 37},
+				panel:      rect{81, 2, 79, 37},
+				sideBySide: true,
+			},
+		},
+		{
+			name: "side by side forced on a narrow terminal", width: 100, height: 40, reader: true, mode: layoutSideBySide, ratio: 0.5,
+			want: screenLayout{
+				list:       rect{0, 2, 50, 37},
+				separator:  rect{50, 2, 1, 37},
+				panel:      rect{51, 2, 49, 37},
+				sideBySide: true,
+			},
+		},
+		{
+			name: "narrow terminal stacks", width: 100, height: 40, reader: true, mode: layoutAuto, ratio: 0.4,
+			want: screenLayout{
+				list:      rect{0, 2, 98, 15},
+				separator: rect{0, 17, 100, 1},
+				panel:     rect{0, 18, 100, 21},
+			},
+		},
+		{
+			name: "stacked forced on a wide terminal", width: 200, height: 40, reader: true, mode: layoutStacked, ratio: 0.5,
+			want: screenLayout{
+				list:      rect{0, 2, 198, 19},
+				separator: rect{0, 21, 200, 1},
+				panel:     rect{0, 22, 200, 17},
+			},
+		},
+		{
+			name: "large ratio leaves the panel its minimum", width: 100, height: 40, reader: true, mode: layoutStacked, ratio: 0.95,
+			want: screenLayout{
+				list:      rect{0, 2, 98, 32},
+				separator: rect{0, 34, 100, 1},
+				panel:     rect{0, 35, 100, 4},
+			},
+		},
+		{
+			name: "unset ratio splits in half", width: 100, height: 40, reader: true, mode: layoutStacked, ratio: 0,
+			want: screenLayout{
+				list:      rect{0, 2, 98, 19},
+				separator: rect{0, 21, 100, 1},
+				panel:     rect{0, 22, 100, 17},
+			},
+		},
+		{
+			name: "small terminal keeps the list minimum", width: 20, height: 8, reader: true, mode: layoutAuto, ratio: 0.5,
+			want: screenLayout{
+				list:      rect{0, 2, 18, 3},
+				separator: rect{0, 5, 20, 1},
+				panel:     rect{0, 6, 20, 1},
+			},
+		},
+		{
+			name: "small terminal side by side", width: 50, height: 10, reader: true, mode: layoutSideBySide, ratio: 0.5,
+			want: screenLayout{
+				list:       rect{0, 2, 30, 7},
+				separator:  rect{30, 2, 1, 7},
+				panel:      rect{31, 2, 19, 7},
+				sideBySide: true,
+			},
+		},
+		{
+			name: "tiny terminal", width: 1, height: 2, mode: layoutAuto, ratio: 0.5,
+			want: screenLayout{list: rect{0, 2, 1, 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := computeLayout(tt.width, tt.height, tt.reader, tt.fullscreen, tt.mode, tt.ratio, 140)
+			if got != tt.want {
+				t.Errorf("computeLayout(%d, %d) =\n  %+v\nwant\n  %+v", tt.width, tt.height, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitRatioIsSavedOutsideConfig(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	saved := appConfig
+	t.Cleanup(func() { appConfig = saved })
+
+	applySavedSplit()
+	if appConfig.TUI.SplitRatio != saved.TUI.SplitRatio {
+		t.Errorf("ratio = %v with no state file, want the configured %v", appConfig.TUI.SplitRatio, saved.TUI.SplitRatio)
+	}
+
+	if err := saveSplitRatio(0.333); err != nil {
+		t.Fatalf("saveSplitRatio: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "redditview", "state.json")); err != nil {
+		t.Fatalf("state file: %v", err)
+	}
+	applySavedSplit()
+	if appConfig.TUI.SplitRatio != 0.33 {
+		t.Errorf("ratio = %v, want the saved 0.33", appConfig.TUI.SplitRatio)
+	}
+}
@@ -11,9 +11,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -22,45 +26,6 @@ import (
 
 const apiBaseURL = "http://localhost:3002/api"
 
-var (
-	// Colors
-	colorOrange     = lipgloss.Color("#FF4500")
-	colorOrangeDark = lipgloss.Color("#FF6B35")
-	colorGold       = lipgloss.Color("#FFD700")
-	colorGreen      = lipgloss.Color("#90EE90")
-	colorWhite      = lipgloss.Color("#FFFFFF")
-	colorGray       = lipgloss.Color("#CCCCCC")
-	colorDarkGray   = lipgloss.Color("#333333")
-	colorBlack      = lipgloss.Color("#000000")
-	colorBlue       = lipgloss.Color("#87CEEB")
-	colorRed        = lipgloss.Color("#FF0000")
-
-	// Styles
-	headerStyle = lipgloss.NewStyle().
-			Background(colorOrange).
-			Foreground(colorWhite).
-			Bold(true).
-			Padding(0, 1)
-
-	selectedStyle = lipgloss.NewStyle().
-			Background(colorOrangeDark).
-			Foreground(colorWhite).
-			Bold(true)
-
-	footerStyle = lipgloss.NewStyle().
-			Background(colorDarkGray).
-			Foreground(colorWhite).
-			Padding(0, 1)
-
-	focusedStyle = lipgloss.NewStyle().
-			Foreground(colorOrange).
-			Bold(true)
-
-	errorStyle = lipgloss.NewStyle().
-			Foreground(colorRed).
-			Bold(true)
-)
-
 // ============= Configuration =============
 
 type AppConfig struct {
@@ -70,7 +35,23 @@ type AppConfig struct {
 		ListHeight         int               `json:"list_height"`
 		MaxTitleLength     int               `json:"max_title_length"`
 		DefaultSort        string            `json:"default_sort"`
+		CommentSort        string            `json:"comment_sort"` // best, top, new, controversial, old, qa
 		SubredditShortcuts map[string]string `json:"subreddit_shortcuts"`
+		ImagePreview       string            `json:"image_preview"` // auto, kitty, iterm2, sixel, halfblock, off
+		ImagePreviewRows   int               `json:"image_preview_rows"`
+		MediaCacheDir      string            `json:"media_cache_dir"`
+		Theme              string            `json:"theme"` // auto, dark, light, high-contrast, solarized or a theme file name
+		ThemeFile          string            `json:"theme_file"`
+		Colors             Theme             `json:"colors"`
+		ListColumns        []string          `json:"list_columns"` // tags, flair, author, subreddit, score, ratio, comments, age, domain, awards
+		Layout             string            `json:"layout"`       // auto, side-by-side, stacked
+		SplitRatio         float64           `json:"split_ratio"`  // list's share of the split view
+		SideBySideWidth    int               `json:"side_by_side_width"`
+		ExportDir          string            `json:"export_dir"`
+		ExportFormat       string            `json:"export_format"`        // markdown, html, json
+		HomeFeed           string            `json:"home_feed"`            // auto, subreddit
+		AutoRefreshSeconds int               `json:"auto_refresh_seconds"` // 0 turns it off
+		FollowSeconds      int               `json:"follow_seconds"`
 	} `json:"tui"`
 	Web struct {
 		DefaultSubreddit string `json:"default_subreddit"`
@@ -81,23 +62,41 @@ type AppConfig struct {
 		BaseURL        string `json:"base_url"`
 		TimeoutSeconds int    `json:"timeout_seconds"`
 	} `json:"api"`
+	Auth struct {
+		Flow         string   `json:"flow"` // installed, script
+		ClientID     string   `json:"client_id"`
+		ClientSecret string   `json:"client_secret"` // script apps only
+		Username     string   `json:"username"`      // script apps only
+		Password     string   `json:"password"`      // script apps only
+		RedirectPort int      `json:"redirect_port"`
+		Scopes       []string `json:"scopes"`
+		UserAgent    string   `json:"user_agent"`
+		TokenFile    string   `json:"token_file"`
+		AuthURL      string   `json:"auth_url"`
+		TokenURL     string   `json:"token_url"`
+		APIURL       string   `json:"api_url"`
+	} `json:"auth"`
+	Watch struct {
+		Rules   []watchRule `json:"rules"`
+		Silent  bool        `json:"silent"`  // no terminal bell
+		Desktop bool        `json:"desktop"` // notify-send, when installed
+		LogFile string      `json:"log_file"`
+	} `json:"watch"`
+	Keybindings map[string][]string `json:"keybindings"`
 }
 
 var appConfig AppConfig
 
+// configPath is config.json in the repository root, relative to apps/tui.
+const configPath = "../../config.json"
+
 func loadConfig() error {
 	// Try to load from config.json in parent directory
-	configPath := "../../config.json"
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		// If not found, use defaults
 		appConfig = AppConfig{}
-		appConfig.TUI.DefaultSubreddit = "sysadmin"
-		appConfig.TUI.PostsPerPage = 50
-		appConfig.TUI.ListHeight = 10
-		appConfig.TUI.MaxTitleLength = 80
-		appConfig.API.BaseURL = "http://localhost:3002/api"
-		appConfig.API.TimeoutSeconds = 10
+		applyConfigDefaults()
 		return nil
 	}
 
@@ -106,7 +105,12 @@ func loadConfig() error {
 		return fmt.Errorf("failed to parse config.json: %w", err)
 	}
 
-	// Set defaults for any missing values
+	applyConfigDefaults()
+	return nil
+}
+
+// applyConfigDefaults sets defaults for any missing values
+func applyConfigDefaults() {
 	if appConfig.TUI.DefaultSubreddit == "" {
 		appConfig.TUI.DefaultSubreddit = "sysadmin"
 	}
@@ -122,17 +126,66 @@ func loadConfig() error {
 	if appConfig.TUI.DefaultSort == "" {
 		appConfig.TUI.DefaultSort = "popular"
 	}
+	if appConfig.TUI.CommentSort == "" {
+		appConfig.TUI.CommentSort = "best"
+	}
 	if appConfig.TUI.SubredditShortcuts == nil {
 		appConfig.TUI.SubredditShortcuts = make(map[string]string)
 	}
+	if appConfig.TUI.ListColumns == nil {
+		appConfig.TUI.ListColumns = []string{"tags", "flair", "author", "score", "comments", "age"}
+	}
+	if appConfig.TUI.ImagePreview == "" {
+		appConfig.TUI.ImagePreview = "auto"
+	}
+	if appConfig.TUI.ImagePreviewRows == 0 {
+		appConfig.TUI.ImagePreviewRows = 16
+	}
+	if appConfig.TUI.Layout == "" {
+		appConfig.TUI.Layout = layoutAuto
+	}
+	if appConfig.TUI.SplitRatio == 0 {
+		appConfig.TUI.SplitRatio = defaultSplitRatio
+	}
+	if appConfig.TUI.SideBySideWidth == 0 {
+		appConfig.TUI.SideBySideWidth = 140
+	}
+	if appConfig.TUI.FollowSeconds == 0 {
+		appConfig.TUI.FollowSeconds = 30
+	}
+	if appConfig.TUI.HomeFeed == "" {
+		appConfig.TUI.HomeFeed = homeFeedAuto
+	}
+	if appConfig.TUI.ExportFormat == "" {
+		appConfig.TUI.ExportFormat = "markdown"
+	}
 	if appConfig.API.BaseURL == "" {
 		appConfig.API.BaseURL = "http://localhost:3002/api"
 	}
 	if appConfig.API.TimeoutSeconds == 0 {
 		appConfig.API.TimeoutSeconds = 10
 	}
-
-	return nil
+	if appConfig.Auth.Flow == "" {
+		appConfig.Auth.Flow = authFlowInstalled
+	}
+	if appConfig.Auth.RedirectPort == 0 {
+		appConfig.Auth.RedirectPort = 65010
+	}
+	if appConfig.Auth.Scopes == nil {
+		appConfig.Auth.Scopes = []string{"identity", "read", "vote", "submit", "flair", "mysubreddits", "privatemessages"}
+	}
+	if appConfig.Auth.UserAgent == "" {
+		appConfig.Auth.UserAgent = "terminal:redditview:v1.0"
+	}
+	if appConfig.Auth.AuthURL == "" {
+		appConfig.Auth.AuthURL = "https://www.reddit.com/api/v1/authorize"
+	}
+	if appConfig.Auth.TokenURL == "" {
+		appConfig.Auth.TokenURL = "https://www.reddit.com/api/v1/access_token"
+	}
+	if appConfig.Auth.APIURL == "" {
+		appConfig.Auth.APIURL = "https://oauth.reddit.com"
+	}
 }
 
 // ============= Data Models =============
@@ -148,6 +201,114 @@ type RedditPostData struct {
 	URL       string  `json:"url"`
 	SubName   string  `json:"subreddit"`
 	Permalink string  `json:"permalink"`
+
+	// Metadata
+	Flair       string       `json:"link_flair_text"`
+	Over18      bool         `json:"over_18"`
+	Spoiler     bool         `json:"spoiler"`
+	Stickied    bool         `json:"stickied"`
+	Locked      bool         `json:"locked"`
+	UpvoteRatio float64      `json:"upvote_ratio"`
+	Domain      string       `json:"domain"`
+	Awards      int          `json:"total_awards_received"`
+	Edited      RedditEdited `json:"edited"`
+	IsSelf      bool         `json:"is_self"`
+	Likes       RedditVote   `json:"likes"`
+
+	// Media
+	PostHint      string                       `json:"post_hint"`
+	IsVideo       bool                         `json:"is_video"`
+	IsGallery     bool                         `json:"is_gallery"`
+	Preview       *RedditPreview               `json:"preview"`
+	GalleryData   *RedditGalleryData           `json:"gallery_data"`
+	MediaMetadata map[string]RedditMediaSource `json:"media_metadata"`
+}
+
+// RedditEdited is the "edited" field, which Reddit sends as false for
+// unedited posts and as the edit's Unix timestamp otherwise.
+type RedditEdited float64
+
+func (e *RedditEdited) UnmarshalJSON(data []byte) error {
+	var ts float64
+	if err := json.Unmarshal(data, &ts); err == nil {
+		*e = RedditEdited(ts)
+		return nil
+	}
+	var edited bool
+	if err := json.Unmarshal(data, &edited); err != nil {
+		return err
+	}
+	*e = 0
+	if edited {
+		*e = 1 // edited, time unknown
+	}
+	return nil
+}
+
+// RedditVote is the user's vote, sent in "likes" as true for an upvote,
+// false for a downvote and null for none.
+type RedditVote int
+
+const (
+	voteDown RedditVote = -1
+	voteNone RedditVote = 0
+	voteUp   RedditVote = 1
+)
+
+func (v *RedditVote) UnmarshalJSON(data []byte) error {
+	var likes *bool
+	if err := json.Unmarshal(data, &likes); err != nil {
+		return err
+	}
+	*v = voteFromLikes(likes)
+	return nil
+}
+
+func voteFromLikes(likes *bool) RedditVote {
+	switch {
+	case likes == nil:
+		return voteNone
+	case *likes:
+		return voteUp
+	}
+	return voteDown
+}
+
+type RedditImage struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+type RedditPreview struct {
+	Images []struct {
+		Source      RedditImage   `json:"source"`
+		Resolutions []RedditImage `json:"resolutions"`
+	} `json:"images"`
+}
+
+type RedditGalleryData struct {
+	Items []struct {
+		MediaID string `json:"media_id"`
+		Caption string `json:"caption"`
+	} `json:"items"`
+}
+
+// RedditMediaSource is an entry of media_metadata; gallery images use the
+// short keys u (URL), x (width) and y (height).
+type RedditMediaSource struct {
+	Status string `json:"status"`
+	Kind   string `json:"e"`
+	Source struct {
+		URL    string `json:"u"`
+		Width  int    `json:"x"`
+		Height int    `json:"y"`
+	} `json:"s"`
+	Previews []struct {
+		URL    string `json:"u"`
+		Width  int    `json:"x"`
+		Height int    `json:"y"`
+	} `json:"p"`
 }
 
 type RedditPost struct {
@@ -169,6 +330,7 @@ type Comment struct {
 	Score     int
 	Created   float64
 	Depth     int
+	Likes     RedditVote
 	Replies   []*Comment
 	Collapsed bool
 }
@@ -176,7 +338,9 @@ type Comment struct {
 // ============= List Item Implementation =============
 
 type PostItem struct {
-	post RedditPostData
+	post         RedditPostData
+	absoluteTime bool
+	fresh        bool // added by auto-refresh and not yet seen
 }
 
 func (p PostItem) FilterValue() string {
@@ -184,11 +348,74 @@ func (p PostItem) FilterValue() string {
 }
 
 func (p PostItem) Title() string {
+	if p.fresh {
+		return "✨ " + p.post.Title
+	}
 	return p.post.Title
 }
 
 func (p PostItem) Description() string {
-	return fmt.Sprintf("u/%s  •  ⬆ %s  •  💬 %s", p.post.Author, formatNum(p.post.Score), formatNum(p.post.Comments))
+	var parts []string
+	for _, column := range appConfig.TUI.ListColumns {
+		if text := p.column(column); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "  •  ")
+}
+
+// column renders one of the configurable list_columns for the row.
+func (p PostItem) column(name string) string {
+	post := p.post
+	switch name {
+	case "tags":
+		return strings.Join(postTags(post), " ")
+	case "flair":
+		if post.Flair != "" {
+			return "[" + post.Flair + "]"
+		}
+	case "author":
+		return "u/" + post.Author
+	case "subreddit":
+		return "r/" + post.SubName
+	case "score":
+		return voteScore(post.Score, post.Likes)
+	case "ratio":
+		if post.UpvoteRatio > 0 {
+			return fmt.Sprintf("%.0f%%", post.UpvoteRatio*100)
+		}
+	case "comments":
+		return "💬 " + formatNum(post.Comments)
+	case "age":
+		return formatTimestamp(post.Created, p.absoluteTime)
+	case "domain":
+		if !post.IsSelf && post.Domain != "" {
+			return post.Domain
+		}
+	case "awards":
+		if post.Awards > 0 {
+			return "🏆 " + formatNum(post.Awards)
+		}
+	}
+	return ""
+}
+
+// postTags returns the short markers for a post's moderation and content flags.
+func postTags(post RedditPostData) []string {
+	var tags []string
+	if post.Stickied {
+		tags = append(tags, "📌")
+	}
+	if post.Locked {
+		tags = append(tags, "🔒")
+	}
+	if post.Over18 {
+		tags = append(tags, "NSFW")
+	}
+	if post.Spoiler {
+		tags = append(tags, "SPOILER")
+	}
+	return tags
 }
 
 // ============= API Client =============
@@ -196,24 +423,40 @@ func (p PostItem) Description() string {
 type APIClient struct {
 	baseURL string
 	client  *http.Client
+	session *session // nil when anonymous
 }
 
+// NewAPIClient returns a client for the proxy, or for Reddit's OAuth API
+// when a login was saved (see auth.go).
 func NewAPIClient() *APIClient {
-	return &APIClient{
+	c := &APIClient{
 		baseURL: appConfig.API.BaseURL,
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
+	if s, err := loadSession(); err == nil && s != nil {
+		c.useSession(s)
+	}
+	return c
 }
 
-func (c *APIClient) FetchPosts(subreddit, sort string) ([]RedditPostData, error) {
+func (c *APIClient) FetchPosts(subreddit, sort string, limit int) ([]RedditPostData, error) {
 	// sort can be: "popular" (hot), "new", "top", "controversial", "rising"
 	// Default to "hot" if not specified
 	if sort == "" || sort == "popular" {
 		sort = "hot"
 	}
-	resp, err := c.client.Get(fmt.Sprintf("%s/r/%s/%s.json?limit=%d", c.baseURL, subreddit, sort, appConfig.TUI.PostsPerPage))
+	path := "/r/" + subreddit + "/" + sort
+	if subreddit == frontPage {
+		// The subscriptions when logged in. The anonymous proxy only routes
+		// subreddits, and Reddit's logged-out front page is r/popular anyway
+		path = "/r/popular/" + sort
+		if c.Authenticated() {
+			path = "/" + sort
+		}
+	}
+	resp, err := c.client.Get(fmt.Sprintf("%s%s.json?limit=%d", c.baseURL, path, limit))
 	if err != nil {
 		return nil, err
 	}
@@ -237,7 +480,7 @@ func (c *APIClient) FetchPosts(subreddit, sort string) ([]RedditPostData, error)
 }
 
 // SearchPosts performs a Reddit-wide search
-func (c *APIClient) SearchPosts(query string) ([]RedditPostData, error) {
+func (c *APIClient) SearchPosts(query string, limit int) ([]RedditPostData, error) {
 	if query == "" {
 		return []RedditPostData{}, nil
 	}
@@ -245,7 +488,7 @@ func (c *APIClient) SearchPosts(query string) ([]RedditPostData, error) {
 	searchURL := fmt.Sprintf("%s/search.json?q=%s&type=link&limit=%d",
 		c.baseURL,
 		url.QueryEscape(query),
-		appConfig.TUI.PostsPerPage)
+		limit)
 
 	resp, err := c.client.Get(searchURL)
 	if err != nil {
@@ -270,11 +513,95 @@ func (c *APIClient) SearchPosts(query string) ([]RedditPostData, error) {
 	return posts, nil
 }
 
-// FetchComments fetches top-level comments for a post
-func (c *APIClient) FetchComments(subreddit, postID string) ([]*Comment, error) {
-	commentsURL := fmt.Sprintf("%s/r/%s/comments/%s/", c.baseURL, subreddit, postID)
+// commentsURL is the proxy URL of a post's comments page. The subreddit may
+// be empty, or frontPage, when only the post ID is known; an empty sort
+// leaves the order to Reddit.
+func (c *APIClient) commentsURL(subreddit, postID, sort string) string {
+	u := fmt.Sprintf("%s/comments/%s/", c.baseURL, postID)
+	if subreddit != "" && subreddit != frontPage {
+		u = fmt.Sprintf("%s/r/%s/comments/%s/", c.baseURL, subreddit, postID)
+	}
+	if sort != "" {
+		u += "?sort=" + url.QueryEscape(commentSortParam(sort))
+	}
+	return u
+}
+
+// FetchPost loads a single post and its comment threads by ID.
+func (c *APIClient) FetchPost(subreddit, postID, sort string) (RedditPostData, []*Comment, error) {
+	return c.fetchPost(c.commentsURL(subreddit, postID, sort), postID, false)
+}
 
-	resp, err := c.client.Get(commentsURL)
+// FetchThread loads a post with as much of its comment tree as Reddit
+// returns in one request, without the limits used for browsing.
+func (c *APIClient) FetchThread(subreddit, postID, sort string) (RedditPostData, []*Comment, error) {
+	return c.fetchPost(c.threadURL(subreddit, postID, sort), postID, true)
+}
+
+// threadURL is commentsURL asking for as many comments as Reddit allows.
+func (c *APIClient) threadURL(subreddit, postID, sort string) string {
+	u := c.commentsURL(subreddit, postID, sort)
+	if strings.Contains(u, "?") {
+		return u + "&limit=500"
+	}
+	return u + "?limit=500"
+}
+
+// FetchContext loads a post with the thread leading to one comment: its
+// parents, the comment and its replies.
+func (c *APIClient) FetchContext(subreddit, postID, commentID string) (RedditPostData, []*Comment, error) {
+	u := c.commentsURL(subreddit, postID, "") + "_/" + commentID + "/?context=8"
+	return c.fetchPost(u, postID, true)
+}
+
+func (c *APIClient) fetchPost(u, postID string, full bool) (RedditPostData, []*Comment, error) {
+	post, listing, err := c.fetchPostListing(u, postID)
+	if err != nil {
+		return RedditPostData{}, nil, err
+	}
+	return post, parseCommentChildren(listing, 0, full), nil
+}
+
+// fetchPostListing loads a post and the raw data of its comment listing.
+func (c *APIClient) fetchPostListing(u, postID string) (RedditPostData, map[string]interface{}, error) {
+	resp, err := c.client.Get(u)
+	if err != nil {
+		return RedditPostData{}, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return RedditPostData{}, nil, fmt.Errorf("post %s: %s", postID, resp.Status)
+	}
+
+	data, _ := io.ReadAll(resp.Body)
+
+	// Reddit returns [post listing, comments listing]
+	var listings []json.RawMessage
+	if err := json.Unmarshal(data, &listings); err != nil || len(listings) < 2 {
+		return RedditPostData{}, nil, fmt.Errorf("failed to parse Reddit API response for post %s", postID)
+	}
+
+	var postListing RedditResponse
+	if err := json.Unmarshal(listings[0], &postListing); err != nil {
+		return RedditPostData{}, nil, fmt.Errorf("failed to parse Reddit API response: %w", err)
+	}
+	if len(postListing.Data.Children) == 0 || postListing.Data.Children[0].Kind != "t3" {
+		return RedditPostData{}, nil, fmt.Errorf("post %s not found", postID)
+	}
+
+	var commentsListing struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(listings[1], &commentsListing); err != nil {
+		return RedditPostData{}, nil, fmt.Errorf("failed to parse Reddit API response: %w", err)
+	}
+
+	return postListing.Data.Children[0].Data, commentsListing.Data, nil
+}
+
+// FetchComments loads a post's comment threads in the given sort order.
+func (c *APIClient) FetchComments(subreddit, postID, sort string) ([]*Comment, error) {
+	resp, err := c.client.Get(c.commentsURL(subreddit, postID, sort))
 	if err != nil {
 		return nil, err
 	}
@@ -307,10 +634,20 @@ func (c *APIClient) FetchComments(subreddit, postID string) ([]*Comment, error)
 	return nil, nil
 }
 
+// maxCommentDepth is how deep reply threads are followed.
+const maxCommentDepth = 6
+
 func parseComments(dataMap map[string]interface{}) ([]*Comment, error) {
+	return parseCommentChildren(dataMap, 0, false), nil
+}
+
+// parseCommentChildren parses one level of a comment listing, following
+// each comment's replies. Unless full is set, only the first few top-level
+// comments and maxCommentDepth levels of replies are kept.
+func parseCommentChildren(dataMap map[string]interface{}, depth int, full bool) []*Comment {
 	childrenInterface, ok := dataMap["children"].([]interface{})
 	if !ok {
-		return nil, nil
+		return nil
 	}
 
 	comments := make([]*Comment, 0)
@@ -331,25 +668,36 @@ func parseComments(dataMap map[string]interface{}) ([]*Comment, error) {
 		}
 
 		comment := &Comment{
-			Author: toString(data["author"]),
-			Body:   toString(data["body"]),
-			Score:  toInt(data["score"]),
+			Author:  toString(data["author"]),
+			Body:    toString(data["body"]),
+			Score:   toInt(data["score"]),
+			Created: toFloat(data["created_utc"]),
+			Depth:   depth,
+		}
+		if likes, ok := data["likes"].(bool); ok {
+			comment.Likes = voteFromLikes(&likes)
 		}
 
 		if idVal, ok := data["id"].(string); ok {
 			comment.ID = idVal
 		}
 
+		// Replies are a nested listing, or "" when there are none
+		if replies, ok := data["replies"].(map[string]interface{}); ok && (full || depth+1 < maxCommentDepth) {
+			if repliesData, ok := replies["data"].(map[string]interface{}); ok {
+				comment.Replies = parseCommentChildren(repliesData, depth+1, full)
+			}
+		}
+
 		comments = append(comments, comment)
-		if len(comments) >= 5 {
+		if !full && depth == 0 && len(comments) >= 5 {
 			break // Limit to top 5 comments
 		}
 	}
 
-	return comments, nil
+	return comments
 }
 
-// Helper functions for type conversions
 func toString(v interface{}) string {
 	if s, ok := v.(string); ok {
 		return s
@@ -357,6 +705,16 @@ func toString(v interface{}) string {
 	return ""
 }
 
+func toFloat(v interface{}) float64 {
+	switch val := v.(type) {
+	case float64:
+		return val
+	case int:
+		return float64(val)
+	}
+	return 0
+}
+
 func toInt(v interface{}) int {
 	switch val := v.(type) {
 	case float64:
@@ -401,6 +759,12 @@ type Model struct {
 	commentsScrollY   int
 	commentsMaxScroll int
 	commentsLoading   bool
+	commentsPostID    string // post the comments belong to
+	commentSort       string // best, top, new, ... for this session
+
+	// Reader: post body and comments in one viewport
+	reading bool
+	reader  viewport.Model
 
 	// List component
 	list list.Model
@@ -408,30 +772,112 @@ type Model struct {
 	// UI Components
 	searchInput    textinput.Model
 	subredditInput textinput.Model
+	gotoInput      textinput.Model
 	spinner        spinner.Model
 
 	// State
-	subreddit    string
+	subreddit    string // frontPage for the logged-in home feed
 	sort         string // "popular" or "new"
 	loading      bool
 	error        string
 	searching    bool
 	selectingSub bool
+	goingTo      bool
 	showDetails  bool
 
+	// Reply composer
+	composer       textarea.Model
+	composing      bool
+	composePreview bool
+	submitting     bool
+	replyTo        replyTarget
+
+	// New post form
+	form    postForm
+	posting bool
+
+	// Subscribed subreddits when logged in, for the picker and shortcuts
+	subscriptions []string
+
+	// Auto-refresh: a background fetch is running, the list holds search
+	// results (which are not refreshed), and the IDs of posts it added
+	refreshing    bool
+	showingSearch bool
+	newPosts      map[string]bool
+
+	// Inbox and the unread count shown in the header
+	inbox     inboxState
+	showInbox bool
+	unread    int
+
+	// Follow mode: the followed post's ID, the current follow session,
+	// whether the whole thread has been merged in yet, and the comments it
+	// added
+	following      string
+	followSession  int
+	followFetching bool
+	followSeeded   bool
+	newComments    map[string]bool
+
+	// Watch alerts: the log, how many arrived since it was last opened, and
+	// the rule/post pairs already matched
+	alerts       []watchAlert
+	showAlerts   bool
+	alertCursor  int
+	unseenAlerts int
+	alerted      map[string]bool
+	watchSeeded  bool
+
+	// Post to open at startup instead of the subreddit listing
+	startPost *postRef
+
 	// Detail view scroll
-	detailScrollY   int
-	detailMaxScroll int
+	detailScrollY int
+
+	// Where the user was before a reload or filter, restored afterwards
+	place place
+
+	// Pending count/key sequence and the last search match
+	motion motionState
+	match  searchMatch
+	query  string
 
 	// Layout
 	windowWidth  int
 	windowHeight int
 
+	// Transient status message and pending "y" copy prefix
+	toast       string
+	toastID     int
+	pendingYank bool
+
+	// Show absolute timestamps instead of "3h ago"
+	absoluteTime bool
+
+	// Downloaded images and rendered previews
+	media *mediaCache
+
+	// Key bindings and the help overlay
+	keys        KeyMap
+	help        help.Model
+	showHelp    bool
+	helpFull    bool
+	helpScrollY int
+
+	// Split view: the list's share of the screen and the full-screen reader
+	splitRatio float64
+	fullscreen bool
+
+	// Mouse: separator drag and the last click for double-click detection
+	dragging    bool
+	lastClick   time.Time
+	lastClickAt int
+
 	// API
 	client *APIClient
 }
 
-func initialModel() Model {
+func initialModel(keys KeyMap) Model {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 
@@ -442,12 +888,18 @@ func initialModel() Model {
 	subInput := textinput.New()
 	subInput.Placeholder = "Enter subreddit (e.g., golang, rust)..."
 	subInput.CharLimit = 50
+	subInput.ShowSuggestions = true
 
-	l := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	gotoInput := textinput.New()
+	gotoInput.Placeholder = "Reddit link, redd.it link or post ID..."
+	gotoInput.CharLimit = 300
+
+	l := list.New([]list.Item{}, newListDelegate(), 0, 0)
 	l.Title = ""
 	l.SetFilteringEnabled(false)
 	l.SetShowFilter(false)
 	l.DisableQuitKeybindings()
+	keys.applyToList(&l)
 
 	m := Model{
 		client:         NewAPIClient(),
@@ -456,11 +908,28 @@ func initialModel() Model {
 		spinner:        s,
 		searchInput:    searchInput,
 		subredditInput: subInput,
+		gotoInput:      gotoInput,
+		composer:       newComposer(),
+		form:           newPostForm(),
 		list:           l,
 		loading:        true,
 		windowWidth:    120,
 		windowHeight:   40,
 		showDetails:    false,
+		media:          newMediaCache(),
+		keys:           keys,
+		help:           newHelpModel(),
+		match:          noMatch,
+		reader:         viewport.New(0, 0),
+		commentSort:    appConfig.TUI.CommentSort,
+		splitRatio:     appConfig.TUI.SplitRatio,
+	}
+	m.subreddit = homeSubreddit(m.client)
+	m.subredditInput.SetSuggestions(m.subredditChoices())
+	m.alerts = loadAlerts()
+	m.alerted = make(map[string]bool, len(m.alerts))
+	for _, a := range m.alerts {
+		m.alerted[a.key()] = true
 	}
 
 	return m
@@ -480,6 +949,8 @@ type searchResultsMsg struct {
 }
 
 type commentsLoadedMsg struct {
+	postID   string
+	sort     string
 	comments []*Comment
 	error    error
 }
@@ -488,7 +959,7 @@ type commentsLoadedMsg struct {
 
 func (m Model) loadPosts(subreddit, sort string) tea.Cmd {
 	return func() tea.Msg {
-		posts, err := m.client.FetchPosts(subreddit, sort)
+		posts, err := m.client.FetchPosts(subreddit, sort, appConfig.TUI.PostsPerPage)
 		if err != nil {
 			return postsLoadedMsg{nil, err}
 		}
@@ -501,7 +972,7 @@ func (m Model) searchReddit(query string) tea.Cmd {
 		if query == "" {
 			return searchResultsMsg{[]RedditPostData{}, "", nil}
 		}
-		posts, err := m.client.SearchPosts(query)
+		posts, err := m.client.SearchPosts(query, appConfig.TUI.PostsPerPage)
 		if err != nil {
 			return searchResultsMsg{nil, query, err}
 		}
@@ -510,17 +981,31 @@ func (m Model) searchReddit(query string) tea.Cmd {
 }
 
 func (m Model) loadComments(subreddit, postID string) tea.Cmd {
+	sort := m.commentSort
 	return func() tea.Msg {
-		comments, err := m.client.FetchComments(subreddit, postID)
-		return commentsLoadedMsg{comments, err}
+		comments, err := m.client.FetchComments(subreddit, postID, sort)
+		return commentsLoadedMsg{postID, sort, comments, err}
 	}
 }
 
 // ============= Update Logic =============
 
 func (m Model) Init() tea.Cmd {
+	if m.startPost != nil {
+		return tea.Batch(
+			m.fetchPost(*m.startPost),
+			m.loadSubscriptions(),
+			m.loadUnreadCount(),
+			scheduleRefresh(),
+			m.spinner.Tick,
+			tea.EnterAltScreen,
+		)
+	}
 	return tea.Batch(
 		m.loadPosts(m.subreddit, m.sort),
+		m.loadSubscriptions(),
+		m.loadUnreadCount(),
+		scheduleRefresh(),
 		m.spinner.Tick,
 		tea.EnterAltScreen,
 	)
@@ -534,7 +1019,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		m, cmd, handled = m.handleKeyPress(msg)
 		if handled {
-			return m, cmd
+			m.updateListSize()
+			m.markSeen()
+			sync := m.syncComments()
+			return m, tea.Batch(cmd, m.loadSelectedMedia(), sync)
 		}
 		// If not handled, fall through to list update
 
@@ -544,11 +1032,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			m.posts = msg.posts
 			m.filteredPosts = msg.posts
+			m.newPosts = nil
+			m.showingSearch = false
 			m.updateListItems()
+			m.checkWatch(msg.posts, false)
 		}
 		m.loading = false
+		if msg.error == nil && m.restorePlace(m.place) {
+			return m, tea.Batch(m.syncComments(), m.loadSelectedMedia())
+		}
+		m.list.Select(0)
 		m.showDetails = false
 		m.detailScrollY = 0
+		m.updateListSize()
 		return m, nil
 
 	case searchResultsMsg:
@@ -557,85 +1053,268 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			m.posts = msg.posts
 			m.filteredPosts = msg.posts
+			m.newPosts = nil
+			m.showingSearch = true
 			m.updateListItems()
 		}
 		m.loading = false
+		if msg.error == nil && m.restorePlace(m.place) {
+			return m, tea.Batch(m.syncComments(), m.loadSelectedMedia())
+		}
+		m.list.Select(0)
 		m.showDetails = false
 		m.detailScrollY = 0
+		m.updateListSize()
 		return m, nil
 
 	case commentsLoadedMsg:
+		if msg.postID != m.commentsPostID || msg.sort != m.commentSort {
+			return m, nil // a post or order we have since moved away from
+		}
 		if msg.error != nil {
 			m.error = msg.error.Error()
 		} else {
 			m.comments = msg.comments
 			m.commentsLoading = false
+			m.followSeeded = false
 			// Calculate max scroll for comments using actual details height
-			listHeight := (m.windowHeight - 8) / 2
-			detailsHeight := m.windowHeight - 8 - listHeight - 1
-			m = m.calculateCommentsMaxScroll(detailsHeight)
+			m = m.calculateCommentsMaxScroll(m.detailsHeight())
+			if m.following == msg.postID && !m.followFetching {
+				return m, m.fetchFollowed()
+			}
 		}
 		return m, nil
 
+	case postLoadedMsg:
+		m.loading = false
+		if msg.error != nil {
+			if len(m.posts) == 0 {
+				m.error = msg.error.Error()
+				return m, nil
+			}
+			return m, m.showToast("⚠ Could not open post: " + msg.error.Error())
+		}
+		if len(m.posts) == 0 && msg.post.SubName != "" {
+			// Opened from the command line: refresh loads the post's subreddit
+			m.subreddit = msg.post.SubName
+		}
+		m.openFetchedPost(msg)
+		return m, m.loadSelectedMedia()
+
+	case copiedMsg:
+		if msg.error != nil {
+			return m, m.showToast(fmt.Sprintf("Copy failed: %v", msg.error))
+		}
+		return m, m.showToast(fmt.Sprintf("📋 Copied %s", msg.label))
+
+	case replySentMsg:
+		m.submitting = false
+		if msg.error != nil {
+			return m, m.showToast("⚠ Reply failed: " + msg.error.Error())
+		}
+		m.composing = false
+		m.composer.Reset()
+		m.composer.Blur()
+		m.replyTo = replyTarget{}
+		m.insertReply(msg.to, msg.comment)
+		return m, m.showToast("✍ Reply posted")
+
+	case refreshTickMsg:
+		return m, m.backgroundRefresh()
+
+	case refreshedMsg:
+		m.refreshing = false
+		if msg.subreddit != m.subreddit || msg.sort != m.sort || m.loading || m.showingSearch {
+			return m, nil // the listing changed while fetching
+		}
+		if msg.error != nil {
+			return m, m.showToast("⚠ Auto-refresh failed: " + msg.error.Error())
+		}
+		m.mergePosts(msg.posts)
+		m.relayout()
+		return m, m.checkWatch(msg.posts, true)
+
+	case watchFetchedMsg:
+		if msg.error != nil {
+			return m, m.showToast("⚠ Watch refresh failed: " + msg.error.Error())
+		}
+		// The first fetch only learns what is already there
+		cmd := m.checkWatch(msg.posts, m.watchSeeded)
+		m.watchSeeded = true
+		return m, cmd
+
+	case followTickMsg:
+		return m, m.followTick(msg)
+
+	case followedMsg:
+		return m, m.applyFollowed(msg)
+
+	case alertLogMsg:
+		if msg.error != nil {
+			return m, m.showToast("⚠ Could not write alert log: " + msg.error.Error())
+		}
+		return m, nil
+
+	case subscriptionsMsg:
+		if msg.error != nil {
+			return m, m.showToast("⚠ Could not load subscriptions: " + msg.error.Error())
+		}
+		m.setSubscriptions(msg.subreddits)
+		return m, nil
+
+	case inboxLoadedMsg:
+		m.inbox.loading = false
+		if msg.error != nil {
+			m.inbox.err = msg.error.Error()
+			return m, m.showToast("⚠ Could not load inbox: " + msg.error.Error())
+		}
+		m.inbox.items = msg.items
+		m.inbox.loaded = true
+		m.inbox.err = ""
+		m.inbox.cursor = max(0, min(m.inbox.cursor, len(m.inbox.visible())-1))
+		m.unread = m.inbox.unread()
+		return m, nil
+
+	case unreadCountMsg:
+		if msg.error == nil {
+			m.unread = msg.count
+		}
+		return m, nil
+
+	case markReadMsg:
+		if msg.error != nil {
+			m.setRead(msg.fullnames, !msg.read)
+			return m, m.showToast("⚠ Could not update inbox: " + msg.error.Error())
+		}
+		return m, nil
+
+	case submitRulesMsg:
+		m.form.setRules(msg)
+		return m, nil
+
+	case postSubmittedMsg:
+		m.form.sending = false
+		if msg.error != nil {
+			return m, m.showToast("⚠ Post failed: " + msg.error.Error())
+		}
+		m.posting = false
+		m.form = newPostForm()
+		m.sizePostForm()
+		ref := postRef{subreddit: msg.subreddit, id: msg.id}
+		return m, tea.Batch(m.showToast("📝 Posted to r/"+msg.subreddit), m.fetchPost(ref))
+
+	case editorDoneMsg:
+		if msg.error != nil {
+			return m, m.showToast("⚠ Editor failed: " + msg.error.Error())
+		}
+		m.composer.SetValue(msg.text)
+		return m, nil
+
+	case voteDoneMsg:
+		if msg.error != nil {
+			m.setVote(msg.fullname, msg.prev)
+			return m, m.showToast("⚠ Vote failed: " + msg.error.Error())
+		}
+		return m, nil
+
+	case exportDoneMsg:
+		if msg.error != nil {
+			return m, m.showToast("⚠ Export failed: " + msg.error.Error())
+		}
+		if msg.missing > 0 {
+			return m, m.showToast(fmt.Sprintf("💾 Exported to %s (%d comments missing)", msg.path, msg.missing))
+		}
+		return m, m.showToast("💾 Exported to " + msg.path)
+
 	case tea.WindowSizeMsg:
 		m.windowWidth = msg.Width
 		m.windowHeight = msg.Height
-		m.updateListSize()
-		// Recalculate max scroll for comments on window resize
-		if m.showComments {
-			detailsHeight := m.windowHeight - 8 - (m.windowHeight-8)/2 - 1
-			m = m.calculateCommentsMaxScroll(detailsHeight)
-		}
+		m.media.resized()
+		m.relayout()
+		m.sizeComposer()
+		m.sizePostForm()
 		return m, nil
 
 	case spinner.TickMsg:
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
+
+	case mediaLoadedMsg:
+		m.mediaLoaded(msg)
+		return m, nil
+
+	case motionTimeoutMsg:
+		m, cmd = m.handleMotionTimeout(msg)
+		return m, cmd
+
+	case tea.MouseMsg:
+		m, cmd = m.handleMouse(msg)
+		m.updateListSize()
+		m.markSeen()
+		sync := m.syncComments()
+		return m, tea.Batch(cmd, m.loadSelectedMedia(), sync)
+
+	case toastExpiredMsg:
+		if msg.id == m.toastID {
+			m.toast = ""
+		}
+		return m, nil
+	}
+
+	// Cursor blinks and other messages for the composer's textarea
+	if m.composing {
+		m.composer, cmd = m.composer.Update(msg)
+		return m, cmd
+	}
+	if m.posting {
+		return m, m.form.updateFocusedInput(msg)
 	}
 
 	// List update for navigation keys
-	if !m.showDetails && !m.searching && !m.selectingSub {
+	if !m.showDetails && !m.searching && !m.selectingSub && !m.goingTo && !m.composing && !m.posting && !m.showInbox && !m.showAlerts {
 		m.list, cmd = m.list.Update(msg)
+		m.markSeen()
 	}
 
 	return m, cmd
 }
 
 func (m *Model) updateListSize() {
-	if m.showDetails {
-		// Show both list and details: list gets 6 items max
-		listHeight := min(6, m.windowHeight/6)
-		if listHeight < 3 {
-			listHeight = 3
-		}
-		m.list.SetSize(m.windowWidth-2, listHeight)
-	} else {
-		// Show full list: responsive height based on terminal size
-		// Reserve 5 lines for header, info bar, and footer
-		listHeight := m.windowHeight - 5
-		if listHeight < 3 {
-			listHeight = 3 // Ensure minimum of 3 items visible
-		}
-		m.list.SetSize(m.windowWidth-2, listHeight)
+	r := m.layout().list
+	if r.h == 0 {
+		// Full-screen reader: the list keeps its size while hidden
+		return
 	}
+	m.list.SetSize(r.w, r.h)
 }
 
 func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	// Reply composer
+	if m.composing {
+		m, cmd := m.handleComposerKey(msg)
+		return m, cmd, true
+	}
+
+	// New post form
+	if m.posting {
+		m, cmd := m.handlePostFormKey(msg)
+		return m, cmd, true
+	}
+
 	// Handle subreddit selection
 	if m.selectingSub {
-		switch msg.String() {
-		case "esc":
+		switch {
+		case key.Matches(msg, m.keys.Cancel):
 			m.selectingSub = false
 			m.subredditInput.Reset()
 			return m, nil, true
-		case "enter":
+		case key.Matches(msg, m.keys.Confirm):
 			newSub := strings.TrimSpace(m.subredditInput.Value())
 			if newSub != "" {
 				m.subreddit = newSub
 				m.subredditInput.Reset()
 				m.selectingSub = false
-				m.loading = true
-				m.showDetails = false
+				m.startLoading()
 				return m, m.loadPosts(newSub, m.sort), true
 			}
 		}
@@ -644,17 +1323,26 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
 		return m, cmd, true
 	}
 
+	// Handle the "go to" prompt
+	if m.goingTo {
+		m, cmd := m.handleGotoKey(msg)
+		return m, cmd, true
+	}
+
 	// Handle search
 	if m.searching {
-		switch msg.String() {
-		case "esc":
+		switch {
+		case key.Matches(msg, m.keys.Cancel):
 			m.searching = false
 			m.searchInput.Reset()
+			m.query = ""
 			m.filterPosts("")
+			m.restorePlace(m.place)
 			return m, nil, true
-		case "enter":
+		case key.Matches(msg, m.keys.Confirm):
 			m.searching = false
 			query := m.searchInput.Value()
+			m.query = query
 			if query != "" {
 				// Perform Reddit-wide search
 				m.loading = true
@@ -665,241 +1353,164 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
 		var cmd tea.Cmd
 		m.searchInput, cmd = m.searchInput.Update(msg)
 		// Live filter as user types
-		m.filterPosts(m.searchInput.Value())
+		m.query = m.searchInput.Value()
+		m.filterPosts(m.query)
+		m.restorePlace(m.place)
 		return m, cmd, true
 	}
 
-	// Detail view navigation
-	if m.showDetails {
-		switch msg.String() {
-		case "esc", "tab":
-			if m.showComments {
-				m.showComments = false
-				m.commentsScrollY = 0
-				return m, nil, true
-			}
-			m.showDetails = false
-			m.detailScrollY = 0
-			return m, nil, true
-		case "up":
-			// Up arrow: Smart navigation in comments
-			// If at top of comments, navigate to previous post
-			// Otherwise, scroll up within comments
-			if m.showDetails && m.showComments && len(m.filteredPosts) > 0 {
-				// In comments view
-				if m.commentsScrollY == 0 {
-					// At top of comments - navigate to previous post
-					idx := m.list.Index()
-					if idx > 0 {
-						m.list.CursorUp()
-						m.commentsScrollY = 0
-						m.detailScrollY = 0
-						m.showComments = false // Auto-close comments for new post
-					}
-				} else {
-					// Not at top - scroll up within comments
-					m.commentsScrollY--
-				}
-			} else if !m.showDetails && len(m.filteredPosts) > 0 {
-				// Navigate to previous post in list view
-				idx := m.list.Index()
-				if idx > 0 {
-					m.list.CursorUp()
-					m.detailScrollY = 0
-					m.commentsScrollY = 0
-				}
-			} else if m.showDetails && !m.showComments {
-				// Scroll up in post detail view
-				if m.detailScrollY > 0 {
-					m.detailScrollY--
-				}
-			}
-			return m, nil, true
-		case "down":
-			// Down arrow: Smart navigation in comments
-			// If at bottom of comments, navigate to next post
-			// Otherwise, scroll down within comments
-			if m.showDetails && m.showComments && len(m.filteredPosts) > 0 {
-				// In comments view
-				if m.commentsScrollY >= m.commentsMaxScroll {
-					// At bottom of comments - navigate to next post
-					idx := m.list.Index()
-					if idx < len(m.filteredPosts)-1 {
-						m.list.CursorDown()
-						m.commentsScrollY = 0
-						m.detailScrollY = 0
-						m.showComments = false // Auto-close comments for new post
-					}
-				} else {
-					// Not at bottom - scroll down within comments
-					if m.commentsScrollY < m.commentsMaxScroll {
-						m.commentsScrollY++
-					}
-				}
-			} else if !m.showDetails && len(m.filteredPosts) > 0 {
-				// Navigate to next post in list view
-				idx := m.list.Index()
-				if idx < len(m.filteredPosts)-1 {
-					m.list.CursorDown()
-					m.detailScrollY = 0
-					m.commentsScrollY = 0
-				}
-			} else if m.showDetails && !m.showComments {
-				// Scroll down in post detail view
-				if m.detailScrollY < m.detailMaxScroll {
-					m.detailScrollY++
-				}
-			}
-			return m, nil, true
-		case "k":
-			// k: scroll details only (not comments)
-			if !m.showComments {
-				if m.detailScrollY > 0 {
-					m.detailScrollY--
-				}
-			}
-			return m, nil, true
-		case "j":
-			// j: scroll details only (not comments)
-			if !m.showComments {
-				if m.detailScrollY < m.detailMaxScroll {
-					m.detailScrollY++
-				}
-			}
-			return m, nil, true
-		case "pgup":
-			// Page up: scroll content
-			if m.showComments {
-				if m.commentsScrollY > 10 {
-					m.commentsScrollY -= 10
-				} else {
-					m.commentsScrollY = 0
-				}
-			} else {
-				if m.detailScrollY > 10 {
-					m.detailScrollY -= 10
-				} else {
-					m.detailScrollY = 0
-				}
-			}
-			return m, nil, true
-		case "pgdn":
-			// Page down: scroll content
-			if m.showComments {
-				if m.commentsScrollY+10 < m.commentsMaxScroll {
-					m.commentsScrollY += 10
-				} else {
-					m.commentsScrollY = m.commentsMaxScroll
-				}
-			} else {
-				if m.detailScrollY+10 < m.detailMaxScroll {
-					m.detailScrollY += 10
-				} else {
-					m.detailScrollY = m.detailMaxScroll
-				}
-			}
-			return m, nil, true
-		case "home":
-			if m.showComments {
-				m.commentsScrollY = 0
-			} else {
-				m.detailScrollY = 0
-			}
-			return m, nil, true
-		case "end":
-			if m.showComments {
-				m.commentsScrollY = m.commentsMaxScroll
-			} else {
-				m.detailScrollY = m.detailMaxScroll
-			}
-			return m, nil, true
-		case "left", "h":
-			// Left/h: previous post (works even when comments are open)
-			if len(m.filteredPosts) > 0 {
-				idx := m.list.Index()
-				if idx > 0 {
-					m.list.CursorUp()
-					m.detailScrollY = 0
-					m.commentsScrollY = 0
-				}
-			}
-			return m, nil, true
-		case "right", "l":
-			// Right/l: next post (works even when comments are open)
-			if len(m.filteredPosts) > 0 {
-				idx := m.list.Index()
-				if idx < len(m.filteredPosts)-1 {
-					m.list.CursorDown()
-					m.detailScrollY = 0
-					m.commentsScrollY = 0
-				}
-			}
-			return m, nil, true
-		}
+	// Help overlay
+	if m.showHelp {
+		m, cmd := m.handleHelpKey(msg)
+		return m, cmd, true
+	}
+	if key.Matches(msg, m.keys.Help) {
+		m.clearMotion()
+		m.showHelp = true
+		m.helpScrollY = 0
+		return m, nil, true
 	}
 
-	// Global shortcuts (also support left/right to navigate in list view)
-	switch msg.String() {
-	case "left", "h":
-		if !m.showDetails && len(m.filteredPosts) > 0 {
-			idx := m.list.Index()
-			if idx > 0 {
-				m.list.CursorUp()
-			}
+	// Inbox
+	if m.showInbox {
+		m, cmd := m.handleInboxKey(msg)
+		return m, cmd, true
+	}
+
+	// Watch alerts
+	if m.showAlerts {
+		m, cmd := m.handleAlertsKey(msg)
+		return m, cmd, true
+	}
+
+	// Copy: the copy key followed by p/y (permalink), u (URL), t (title), m (markdown) or c (comment)
+	if m.pendingYank {
+		m.pendingYank = false
+		m, cmd := m.handleYank(msg.String())
+		return m, cmd, true
+	}
+	if key.Matches(msg, m.keys.Copy) {
+		m.clearMotion()
+		m.pendingYank = true
+		return m, m.showToast("Copy: y/p permalink  u URL  t title  m markdown  c comment"), true
+	}
+
+	// Counts, key sequences and motions in the list, details and comments
+	var cmd tea.Cmd
+	var handled bool
+	if m, cmd, handled = m.handleMotion(msg); handled {
+		return m, cmd, true
+	}
+
+	// Detail view navigation
+	if m.showDetails && key.Matches(msg, m.keys.Back) {
+		if m.reading {
+			m.reading = false
 			return m, nil, true
 		}
-	case "right", "l":
-		if !m.showDetails && len(m.filteredPosts) > 0 {
-			idx := m.list.Index()
-			if idx < len(m.filteredPosts)-1 {
-				m.list.CursorDown()
-			}
+		if m.showComments {
+			m.showComments = false
+			m.commentsScrollY = 0
 			return m, nil, true
 		}
+		m.showDetails = false
+		m.detailScrollY = 0
+		return m, nil, true
 	}
 
-	// Global shortcuts (remaining)
-	switch msg.String() {
-	case "ctrl+c", "q":
+	// Global shortcuts
+	switch {
+	case key.Matches(msg, m.keys.Quit):
 		return m, tea.Quit, true
-	case "ctrl+f":
+	case key.Matches(msg, m.keys.Search):
+		m.place = m.currentPlace()
 		m.searching = true
 		m.searchInput.Focus()
 		return m, nil, true
-	case "ctrl+r":
+	case key.Matches(msg, m.keys.GoTo):
+		m.goingTo = true
+		m.gotoInput.Focus()
+		return m, nil, true
+	case key.Matches(msg, m.keys.Subreddit):
 		m.selectingSub = true
 		m.subredditInput.Focus()
-		m.subredditInput.SetValue(m.subreddit)
+		if m.subreddit != frontPage {
+			m.subredditInput.SetValue(m.subreddit)
+		}
 		return m, nil, true
-	case "f5":
-		m.loading = true
-		m.showDetails = false
+	case key.Matches(msg, m.keys.Inbox):
+		cmd := m.openInbox()
+		return m, cmd, true
+	case key.Matches(msg, m.keys.Alerts):
+		m.openAlerts()
+		return m, nil, true
+	case key.Matches(msg, m.keys.Home):
+		return m, m.goHome(), true
+	case key.Matches(msg, m.keys.Refresh):
+		m.startLoading()
 		return m, m.loadPosts(m.subreddit, m.sort), true
-	case "t":
+	case key.Matches(msg, m.keys.ToggleSort):
 		// Toggle between popular and new posts
 		if m.sort == "popular" {
 			m.sort = "new"
 		} else {
 			m.sort = "popular"
 		}
-		m.loading = true
-		m.showDetails = false
+		m.startLoading()
 		return m, m.loadPosts(m.subreddit, m.sort), true
-	case "c":
+	case key.Matches(msg, m.keys.Comments):
 		if m.showComments {
 			// Close comments panel
 			m.showComments = false
 			m.commentsScrollY = 0
 		} else if m.showDetails && len(m.filteredPosts) > 0 {
+			m.match = noMatch
 			// Open comments panel
 			m.showComments = true
+			m.reading = false
 			m.commentsScrollY = 0
-			m.commentsLoading = true
-			post := m.filteredPosts[m.list.Index()]
-			return m, m.loadComments(m.subreddit, post.ID), true
 		}
 		return m, nil, true
-	case "w":
+	case key.Matches(msg, m.keys.Reader):
+		if m.reading {
+			m.reading = false
+		} else {
+			m.openReader()
+		}
+		return m, nil, true
+	case key.Matches(msg, m.keys.Upvote):
+		cmd := m.vote(voteUp)
+		return m, cmd, true
+	case key.Matches(msg, m.keys.Downvote):
+		cmd := m.vote(voteDown)
+		return m, cmd, true
+	case key.Matches(msg, m.keys.ClearVote):
+		cmd := m.vote(voteNone)
+		return m, cmd, true
+	case key.Matches(msg, m.keys.Reply):
+		cmd := m.startReply()
+		return m, cmd, true
+	case key.Matches(msg, m.keys.NewPost):
+		cmd := m.openPostForm()
+		return m, cmd, true
+	case key.Matches(msg, m.keys.Export):
+		cmd := m.exportSelected()
+		if cmd == nil {
+			return m, nil, true
+		}
+		return m, tea.Batch(m.showToast("💾 Exporting..."), cmd), true
+	case key.Matches(msg, m.keys.Follow):
+		cmd := m.toggleFollow()
+		return m, cmd, true
+	case key.Matches(msg, m.keys.CommentSort):
+		m.cycleCommentSort()
+		return m, m.showToast("💬 Comments: " + commentSortChoices(m.commentSort)), true
+	case key.Matches(msg, m.keys.JumpComments):
+		if m.reading {
+			m.jumpReaderSection()
+		}
+		return m, nil, true
+	case key.Matches(msg, m.keys.OpenURL):
 		// Open current post URL in browser
 		if len(m.filteredPosts) > 0 {
 			post := m.filteredPosts[m.list.Index()]
@@ -919,30 +1530,59 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
 			}
 		}
 		return m, nil, true
-	case "enter":
+	case key.Matches(msg, m.keys.AbsoluteTime):
+		m.absoluteTime = !m.absoluteTime
+		m.updateListItems()
+		return m, nil, true
+	case key.Matches(msg, m.keys.Fullscreen):
+		// From the list, open the selected post straight into the reader
+		if !m.showDetails && len(m.filteredPosts) > 0 {
+			m.showDetails = true
+			m.detailScrollY = 0
+			m.showComments = false
+			m.fullscreen = true
+		} else {
+			m.fullscreen = !m.fullscreen
+		}
+		m.relayout()
+		return m, nil, true
+	case key.Matches(msg, m.keys.ShrinkList, m.keys.GrowList):
+		if !m.showDetails || m.fullscreen {
+			return m, nil, true
+		}
+		step := splitRatioStep
+		if key.Matches(msg, m.keys.ShrinkList) {
+			step = -step
+		}
+		m.setSplitRatio(m.splitRatio + step)
+		return m, m.saveSplit(), true
+	case key.Matches(msg, m.keys.Open):
 		if len(m.filteredPosts) > 0 && m.list.Index() < len(m.filteredPosts) {
 			m.showDetails = true
 			m.detailScrollY = 0
 			m.showComments = false
+			m.reading = false
 		}
 		return m, nil, true
+	}
+
 	// Subreddit shortcuts (1-9 keys)
-	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
-		if sub, exists := appConfig.TUI.SubredditShortcuts[msg.String()]; exists {
-			m.subreddit = sub
-			m.loading = true
-			m.showDetails = false
-			m.searchInput.Reset()
-			m.searching = false
-			return m, m.loadPosts(sub, m.sort), true
-		}
+	if sub, exists := m.shortcuts()[msg.String()]; exists {
+		m.subreddit = sub
+		m.startLoading()
+		m.searchInput.Reset()
+		m.searching = false
+		return m, m.loadPosts(sub, m.sort), true
 	}
 
 	// Key not handled - let list component handle it
 	return m, nil, false
 }
 
+// filterPosts lists the posts whose title or author contains query. The
+// cursor stays on the selected post when it is still listed.
 func (m *Model) filterPosts(query string) {
+	selected := m.currentPlace().postID
 	query = strings.ToLower(query)
 	if query == "" {
 		m.filteredPosts = m.posts
@@ -956,38 +1596,122 @@ func (m *Model) filterPosts(query string) {
 		}
 	}
 	m.updateListItems()
+	if !m.selectPostID(selected) && m.list.Index() >= len(m.filteredPosts) {
+		m.list.Select(max(0, len(m.filteredPosts)-1))
+	}
 }
 
 func (m *Model) updateListItems() {
 	items := make([]list.Item, len(m.filteredPosts))
 	for i, post := range m.filteredPosts {
-		items[i] = PostItem{post}
+		items[i] = m.postItem(post)
 	}
 	m.list.SetItems(items)
 }
 
+func (m Model) postItem(post RedditPostData) PostItem {
+	return PostItem{post, m.absoluteTime, m.newPosts[post.ID]}
+}
+
 // ============= Helpers =============
 
+// detailsHeight is the height of the detail/comments panel.
+func (m Model) detailsHeight() int {
+	return m.layout().panel.h
+}
+
 func (m Model) calculateCommentsMaxScroll(height int) Model {
-	if len(m.comments) == 0 {
-		m.commentsMaxScroll = 0
-		return m
+	lines, _ := m.commentLines()
+	m.commentsMaxScroll = max(0, len(lines)-height+4)
+	return m
+}
+
+// flatComments lists the comment threads depth first, in display order.
+func (m Model) flatComments() []*Comment {
+	var flat []*Comment
+	var walk func([]*Comment)
+	walk = func(comments []*Comment) {
+		for _, c := range comments {
+			flat = append(flat, c)
+			walk(c.Replies)
+		}
 	}
+	walk(m.comments)
+	return flat
+}
+
+// commentLines lays out the comment threads, returning the lines and the
+// index of each comment's first line, in flatComments order. Replies are
+// indented under a rule for each level.
+func (m Model) commentLines() ([]string, []int) {
+	var lines []string
+	flat := m.flatComments()
+	starts := make([]int, 0, len(flat))
+	for _, comment := range flat {
+		starts = append(starts, len(lines))
+		indent := styles.Separator.Render(strings.Repeat("│ ", comment.Depth))
 
-	// Build comment lines to calculate total
-	var commentLines []string
-	for _, comment := range m.comments {
-		commentLines = append(commentLines, "author line")
+		// Author and score
+		author := fmt.Sprintf("👤 u/%s  •  %s", comment.Author, voteScore(comment.Score, comment.Likes))
+		if age := formatTimestamp(comment.Created, m.absoluteTime); age != "" {
+			author += "  •  " + age
+		}
+		if m.newComments[comment.ID] {
+			lines = append(lines, indent+styles.Focused.Render("✨ "+author))
+		} else {
+			lines = append(lines, indent+styles.Meta.Render(author))
+		}
+
+		// Comment body with wrapping
 		if comment.Body != "" {
-			wrapped := wrapText(comment.Body, m.windowWidth-6)
-			wrappedLines := strings.Split(wrapped, "\n")
-			commentLines = append(commentLines, wrappedLines...)
+			wrapped := wrapText(comment.Body, max(20, m.panelWidth()-6-2*comment.Depth))
+			for _, line := range strings.Split(wrapped, "\n") {
+				lines = append(lines, indent+"  "+line)
+			}
 		}
-		commentLines = append(commentLines, "") // Blank line between comments
+		lines = append(lines, indent) // Blank line between comments
 	}
+	return lines, starts
+}
 
-	m.commentsMaxScroll = max(0, len(commentLines)-height+4)
-	return m
+// detailLines returns the scrollable part of a post's detail panel: media,
+// body text and link.
+func (m Model) detailLines(post RedditPostData) []string {
+	width := m.panelWidth()
+	contentLines := m.renderMediaLines(post, width-4)
+	if post.SelfText != "" {
+		content := wrapText(post.SelfText, width-4)
+		contentLines = append(contentLines, strings.Split(content, "\n")...)
+	}
+
+	// Add URL if present
+	if post.URL != "" && !strings.HasPrefix(post.URL, "https://www.reddit.com") {
+		contentLines = append(contentLines, "")
+		displayURL := post.URL
+		if len(displayURL) > width-10 {
+			displayURL = displayURL[:max(0, width-13)] + "..."
+		}
+		contentLines = append(contentLines, "🔗 "+displayURL)
+	}
+	return contentLines
+}
+
+// detailVisibleLines is how many detail lines fit below the post header.
+func (m Model) detailVisibleLines(post RedditPostData) int {
+	header := 3 // title, meta and blank line
+	if renderBadges(post) != "" {
+		header++
+	}
+	return max(1, m.detailsHeight()-header)
+}
+
+// detailMaxScroll is the largest useful scroll offset of the detail panel.
+func (m Model) detailMaxScroll() int {
+	if m.list.Index() >= len(m.filteredPosts) {
+		return 0
+	}
+	post := m.filteredPosts[m.list.Index()]
+	return max(0, len(m.detailLines(post))-m.detailVisibleLines(post))
 }
 
 // ============= Rendering =============
@@ -1001,43 +1725,92 @@ func (m Model) View() string {
 		return m.renderLoading()
 	}
 
+	if m.showHelp {
+		return m.renderHelp()
+	}
+
 	return m.renderMain()
 }
 
 func (m Model) renderError() string {
-	return errorStyle.Render(fmt.Sprintf("❌ Error: %s\n\nPress q to quit", m.error))
+	return styles.Error.Render(fmt.Sprintf("❌ Error: %s\n\nPress %s to quit", m.error, primaryKey(m.keys.Quit)))
 }
 
 func (m Model) renderLoading() string {
-	return lipgloss.NewStyle().
-		Foreground(colorGold).
+	what := feedName(m.subreddit)
+	if m.startPost != nil && len(m.posts) == 0 {
+		what = "post " + m.startPost.id
+	}
+	return styles.Meta.
 		Padding(2, 4).
-		Render(fmt.Sprintf("%s Loading r/%s...", m.spinner.View(), m.subreddit))
+		Render(fmt.Sprintf("%s Loading %s...", m.spinner.View(), what))
 }
 
 func (m *Model) renderMain() string {
 	// Header
-	header := headerStyle.Render(fmt.Sprintf("  🔥 r/%s  %d posts", m.subreddit, len(m.filteredPosts)))
+	title := fmt.Sprintf("  🔥 %s  %d posts", feedName(m.subreddit), len(m.filteredPosts))
+	if m.subreddit == frontPage {
+		title = fmt.Sprintf("  🏠 %s  %d posts", feedName(m.subreddit), len(m.filteredPosts))
+	}
+	if user := m.client.Username(); user != "" {
+		title += "  👤 u/" + user
+	}
+	if m.unread > 0 {
+		title += fmt.Sprintf("  ✉ %d unread", m.unread)
+	}
+	if m.unseenAlerts > 0 {
+		title += fmt.Sprintf("  🔔 %d", m.unseenAlerts)
+	}
+	if n := len(m.newPosts); n == 1 {
+		title += "  ✨ 1 new post"
+	} else if n > 1 {
+		title += fmt.Sprintf("  ✨ %d new posts", n)
+	}
+	header := styles.Header.Render(title)
+	if activeGraphics == graphicsKitty && !m.showingImage() {
+		// Kitty images outlive the text they were drawn over
+		header = kittyClearSeq + header
+	}
 
 	// Info bar
 	var infoBar string
 	if m.searching {
-		infoBar = lipgloss.NewStyle().
-			Foreground(colorGold).
-			Padding(0, 1).
-			Render(fmt.Sprintf("🔍 Search: %s", m.searchInput.View()))
+		infoBar = styles.Prompt.Render(fmt.Sprintf("🔍 Search: %s", m.searchInput.View()))
 	} else if m.selectingSub {
-		infoBar = lipgloss.NewStyle().
-			Foreground(colorGold).
-			Padding(0, 1).
-			Render(fmt.Sprintf("📍 Subreddit: %s", m.subredditInput.View()))
+		infoBar = styles.Prompt.Render(fmt.Sprintf("📍 Subreddit: %s", m.subredditInput.View()))
+	} else if m.goingTo {
+		infoBar = styles.Prompt.Render(fmt.Sprintf("🔗 Go to: %s", m.gotoInput.View()))
+	} else if m.composing && m.toast == "" {
+		infoBar = styles.Prompt.Render("✍ Markdown: **bold**  *italic*  `code`  > quote  - list  [text](link)")
+	} else if m.showInbox && m.toast == "" {
+		infoBar = styles.Prompt.Render(m.inboxTabBar())
+	} else if m.showAlerts && m.toast == "" {
+		infoBar = styles.Prompt.Render(m.alertsTitle())
+	} else if m.posting && m.toast == "" {
+		infoBar = styles.Prompt.Render("📝 " + joinHints("  ",
+			keyHint("next field", m.keys.NextField),
+			keyHint("previous", m.keys.PrevField),
+			keyHint("toggle", m.keys.Toggle),
+			"←/→ choose type and flair"))
+	} else if pending := m.motion.pending(); pending != "" {
+		infoBar = styles.Prompt.Render("⌨ " + pending)
+	} else if m.toast != "" {
+		infoBar = styles.Prompt.Render(m.toast)
 	} else {
 		infoBar = m.renderInfoBar()
 	}
 
 	// Content
 	var content string
-	if m.showDetails && len(m.filteredPosts) > 0 {
+	if m.composing {
+		content = m.renderComposer()
+	} else if m.posting {
+		content = m.renderPostForm()
+	} else if m.showInbox {
+		content = m.renderInbox()
+	} else if m.showAlerts {
+		content = m.renderAlerts()
+	} else if m.showDetails && len(m.filteredPosts) > 0 {
 		content = m.renderWithDetails()
 	} else {
 		content = m.renderListOnly()
@@ -1049,17 +1822,41 @@ func (m *Model) renderMain() string {
 	return fmt.Sprintf("%s\n%s\n%s\n%s", header, infoBar, content, footer)
 }
 
+// showingImage reports whether the detail panel currently holds an image preview.
+func (m Model) showingImage() bool {
+	if !m.showDetails || m.showComments || m.list.Index() >= len(m.filteredPosts) {
+		return false
+	}
+	urls := m.filteredPosts[m.list.Index()].MediaImages()
+	if len(urls) == 0 {
+		return false
+	}
+	entry := m.media.images[urls[0]]
+	return entry != nil && entry.err == nil && !entry.bounds.Empty()
+}
+
 func (m Model) renderInfoBar() string {
+	k := m.keys
 	if m.showDetails {
-		return lipgloss.NewStyle().
-			Foreground(colorGreen).
-			Padding(0, 1).
-			Render("▲/▼ (k/j): scroll  Home/End: jump  Esc/Tab: back  Ctrl+F: search  F5: refresh  q: quit")
-	}
-	return lipgloss.NewStyle().
-		Foreground(colorGreen).
-		Padding(0, 1).
-		Render("▲/▼ (k/j): navigate  Enter: view  Ctrl+F: search  Ctrl+R: subreddit  F5: refresh  q: quit")
+		return styles.Hint.Render(joinHints("  ",
+			keyHint("scroll", k.Up, k.Down),
+			keyHint("jump", k.Top, k.Bottom),
+			keyHint("full screen", k.Fullscreen),
+			keyHint("back", k.Back),
+			keyHint("search", k.Search),
+			keyHint("refresh", k.Refresh),
+			keyHint("help", k.Help),
+			keyHint("quit", k.Quit)))
+	}
+	return styles.Hint.Render(joinHints("  ",
+		keyHint("navigate", k.Up, k.Down),
+		keyHint("view", k.Open),
+		keyHint("read", k.Reader),
+		keyHint("search", k.Search),
+		keyHint("subreddit", k.Subreddit),
+		keyHint("refresh", k.Refresh),
+		keyHint("help", k.Help),
+		keyHint("quit", k.Quit)))
 }
 
 func (m *Model) renderListOnly() string {
@@ -1068,65 +1865,56 @@ func (m *Model) renderListOnly() string {
 }
 
 func (m *Model) renderWithDetails() string {
-	// Split view: list on top, details on bottom
-	listHeight := (m.windowHeight - 8) / 2
-	detailsHeight := m.windowHeight - 8 - listHeight - 1
+	lay := m.layout()
 
-	m.list.SetSize(m.windowWidth-2, listHeight)
-	listView := m.list.View()
-
-	// Details section or comments
+	// Details section, comments or reader
 	var contentView string
-	if m.showComments {
-		contentView = m.renderCommentsPanel(detailsHeight)
+	if m.reading {
+		contentView = m.renderReader(lay.panel.h)
+	} else if m.showComments {
+		contentView = m.renderCommentsPanel(lay.panel.h)
 	} else {
-		contentView = m.renderDetailsSection(detailsHeight)
+		contentView = m.renderDetailsSection(lay.panel.h)
+	}
+	if lay.list.h == 0 {
+		// Full-screen reader
+		return contentView
 	}
 
-	separator := lipgloss.NewStyle().
-		Foreground(colorOrange).
-		Render(strings.Repeat("─", m.windowWidth-2))
+	m.list.SetSize(lay.list.w, lay.list.h)
+	listView := m.list.View()
+
+	if lay.sideBySide {
+		// List on the left, details on the right
+		listView = lipgloss.NewStyle().Width(lay.list.w).Height(lay.list.h).Render(listView)
+		separator := styles.Separator.Render(strings.TrimSuffix(strings.Repeat("│\n", lay.separator.h), "\n"))
+		return lipgloss.JoinHorizontal(lipgloss.Top, listView, separator, contentView)
+	}
 
+	// List on top, details on bottom
+	separator := styles.Separator.Render(strings.Repeat("─", lay.separator.w))
 	return fmt.Sprintf("%s\n%s\n%s", listView, separator, contentView)
 }
 
 func (m Model) renderCommentsPanel(height int) string {
 	if len(m.comments) == 0 {
 		if m.commentsLoading {
-			return lipgloss.NewStyle().
-				Foreground(colorGray).
+			return styles.Body.
 				Padding(1, 1).
 				Height(height).
 				Render("💬 Loading comments...")
 		}
-		return lipgloss.NewStyle().
-			Foreground(colorGray).
+		return styles.Body.
 			Padding(1, 1).
 			Height(height).
 			Render("💬 No comments found")
 	}
 
 	var sb strings.Builder
-	sb.WriteString(focusedStyle.Render("💬 Comments\n\n"))
+	sb.WriteString(styles.Focused.Render("💬 Comments") + styles.Meta.Render("  "+m.commentSortHint()) + "\n\n")
 
-	// Build comment lines
-	var commentLines []string
-	for _, comment := range m.comments {
-		// Author and score
-		authorLine := lipgloss.NewStyle().Foreground(colorGold).Render(
-			fmt.Sprintf("👤 u/%s  •  ⬆ %s", comment.Author, formatNum(comment.Score)))
-		commentLines = append(commentLines, authorLine)
-
-		// Comment body with wrapping
-		if comment.Body != "" {
-			wrapped := wrapText(comment.Body, m.windowWidth-6)
-			wrappedLines := strings.Split(wrapped, "\n")
-			for _, line := range wrappedLines {
-				commentLines = append(commentLines, "  "+line)
-			}
-		}
-		commentLines = append(commentLines, "") // Blank line between comments
-	}
+	commentLines, _ := m.commentLines()
+	commentLines = m.highlightMatch(paneComments, commentLines)
 
 	// Apply scrolling
 	startLine := m.commentsScrollY
@@ -1140,8 +1928,7 @@ func (m Model) renderCommentsPanel(height int) string {
 		sb.WriteString(strings.Join(visibleLines, "\n"))
 	}
 
-	return lipgloss.NewStyle().
-		Foreground(colorGray).
+	return styles.Body.
 		Padding(0, 1).
 		Height(height).
 		Render(sb.String())
@@ -1157,81 +1944,170 @@ func (m Model) renderDetailsSection(height int) string {
 	var sb strings.Builder
 
 	// Title
-	sb.WriteString(focusedStyle.Render(fmt.Sprintf("📄 %s\n", post.Title)))
-
-	// Meta
-	sb.WriteString(lipgloss.NewStyle().Foreground(colorGold).Render(
-		fmt.Sprintf("👤 u/%s  •  r/%s  •  ⬆ %s  •  💬 %s\n\n",
-			post.Author, post.SubName, formatNum(post.Score), formatNum(post.Comments))))
+	sb.WriteString(styles.Focused.Render("📄 "+post.Title) + "\n")
 
-	// Content
-	var contentLines []string
-	if post.SelfText != "" {
-		content := wrapText(post.SelfText, m.windowWidth-4)
-		contentLines = strings.Split(content, "\n")
+	// Badges
+	if badges := renderBadges(post); badges != "" {
+		sb.WriteString(badges + "\n")
 	}
 
-	// Add URL if present
-	if post.URL != "" && !strings.HasPrefix(post.URL, "https://www.reddit.com") {
-		contentLines = append(contentLines, "")
-		displayURL := post.URL
-		if len(displayURL) > m.windowWidth-10 {
-			displayURL = displayURL[:m.windowWidth-13] + "..."
-		}
-		contentLines = append(contentLines, "🔗 "+displayURL)
-	}
+	// Meta
+	sb.WriteString(styles.Meta.Render(m.postMeta(post)) + "\n\n")
 
-	// Note: Comments section disabled until API endpoint is fixed
-	// Comments fetching and display will be re-enabled in a future update
+	// Content
+	contentLines := m.highlightMatch(paneDetails, m.detailLines(post))
 
 	// Apply scrolling
 	startLine := m.detailScrollY
-	endLine := startLine + height - 3
+	endLine := startLine + m.detailVisibleLines(post)
 	if endLine > len(contentLines) {
 		endLine = len(contentLines)
 	}
 
-	// Calculate max scroll
-	m.detailMaxScroll = max(0, len(contentLines)-height+3)
-
 	if startLine < len(contentLines) {
 		visibleLines := contentLines[startLine:endLine]
 		sb.WriteString(strings.Join(visibleLines, "\n"))
 	}
 
-	return lipgloss.NewStyle().
-		Foreground(colorGray).
+	return styles.Body.
 		Padding(0, 1).
 		Height(height).
 		Render(sb.String())
 }
 
+// postMeta is the author/score line shown under a post's title.
+func (m Model) postMeta(post RedditPostData) string {
+	meta := fmt.Sprintf("👤 u/%s  •  r/%s  •  %s", post.Author, post.SubName, voteScore(post.Score, post.Likes))
+	if post.UpvoteRatio > 0 {
+		meta += fmt.Sprintf(" (%.0f%%)", post.UpvoteRatio*100)
+	}
+	meta += "  •  💬 " + formatNum(post.Comments)
+	if post.Awards > 0 {
+		meta += "  •  🏆 " + formatNum(post.Awards)
+	}
+	if !post.IsSelf && post.Domain != "" {
+		meta += "  •  " + post.Domain
+	}
+	if age := formatTimestamp(post.Created, m.absoluteTime); age != "" {
+		meta += "  •  " + age
+	}
+	if post.Edited != 0 {
+		meta += " (edited)"
+	}
+	return meta
+}
+
+// renderBadges renders the pinned/locked markers, content warnings and flair
+// shown above a post's metadata line.
+func renderBadges(post RedditPostData) string {
+	var badges []string
+	if post.Stickied {
+		badges = append(badges, lipgloss.NewStyle().Foreground(theme.Success).Render("📌 Pinned"))
+	}
+	if post.Locked {
+		badges = append(badges, styles.Meta.Render("🔒 Locked"))
+	}
+	if post.Over18 {
+		badges = append(badges, styles.Badge.Background(theme.Error).Foreground(theme.OnAccent).Render("NSFW"))
+	}
+	if post.Spoiler {
+		badges = append(badges, styles.Badge.Background(theme.Surface).Foreground(theme.OnSurface).Render("SPOILER"))
+	}
+	if post.Flair != "" {
+		badges = append(badges, styles.Badge.Background(theme.Info).Foreground(theme.OnInfo).Render(post.Flair))
+	}
+	return strings.Join(badges, " ")
+}
+
 func (m Model) renderFooter() string {
+	k := m.keys
+	const sep = "  •  "
+	if m.composing {
+		return styles.Footer.Render(joinHints(sep,
+			keyHint("send", k.Submit),
+			keyHint("preview", k.Preview),
+			keyHint("edit in $EDITOR", k.Editor),
+			keyHint("close (keeps draft)", k.Cancel)))
+	}
+	if m.showInbox {
+		open := "open thread"
+		if it := m.inbox.selected(); it != nil && it.message() {
+			open = "mark read"
+		}
+		return styles.Footer.Render(joinHints(sep,
+			keyHint("move", k.Up, k.Down),
+			keyHint("tabs", k.PrevPost, k.NextPost),
+			keyHint(open, k.Open),
+			keyHint("read/unread", k.MarkRead),
+			keyHint("all read", k.MarkAllRead),
+			keyHint("refresh", k.Refresh),
+			keyHint("close", k.Inbox, k.Back)))
+	}
+	if m.showAlerts {
+		return styles.Footer.Render(joinHints(sep,
+			keyHint("move", k.Up, k.Down),
+			keyHint("open post", k.Open),
+			keyHint("close", k.Alerts, k.Back)))
+	}
+	if m.posting {
+		return styles.Footer.Render(joinHints(sep,
+			keyHint("post", k.Submit),
+			keyHint("fields", k.NextField, k.PrevField),
+			keyHint("close (keeps draft)", k.Cancel)))
+	}
 	if m.showDetails {
+		if m.reading {
+			return styles.Footer.Render(joinHints(sep,
+				keyHint("scroll", k.Up, k.Down),
+				keyHint("body/comments", k.JumpComments),
+				keyHint("sort", k.CommentSort),
+				keyHint("follow", k.Follow),
+				keyHint("vote", k.Upvote, k.Downvote),
+				keyHint("reply", k.Reply),
+				keyHint("switch posts", k.PrevPost, k.NextPost),
+				keyHint("open URL", k.OpenURL),
+				keyHint("export", k.Export),
+				keyHint("close reader", k.Back),
+				keyHint("quit", k.Quit)))
+		}
 		if m.showComments {
+			hints := joinHints(sep,
+				keyHint("switch posts", k.PrevPost, k.NextPost),
+				keyHint("open URL", k.OpenURL),
+				keyHint("sort", k.CommentSort),
+				keyHint("follow", k.Follow),
+				keyHint("vote", k.Upvote, k.Downvote),
+				keyHint("reply", k.Reply),
+				keyHint("close comments", k.Back),
+				keyHint("search", k.Search),
+				keyHint("toggle sort", k.ToggleSort),
+				keyHint("quit", k.Quit))
+
 			// Check for comment boundary warnings
 			atTopOfComments := m.commentsScrollY == 0
 			atBottomOfComments := m.commentsScrollY >= m.commentsMaxScroll
 
-			var warningStyle lipgloss.Style
-			var footerText string
-
 			if atTopOfComments && m.list.Index() > 0 {
 				// At top of comments with previous post available
-				warningStyle = lipgloss.NewStyle().Foreground(colorOrange)
-				footerText = "⚠️  Next ↑ will load previous post  •  h/l: switch posts  •  w: open URL  •  Esc: close comments  •  Ctrl+F: search  •  t: toggle sort  •  q: quit"
-				return warningStyle.Render(footerText)
+				return styles.Warning.Render(fmt.Sprintf("⚠️  Next %s will load previous post%s%s", primaryKey(k.Up), sep, hints))
 			} else if atBottomOfComments && m.list.Index() < len(m.filteredPosts)-1 {
 				// At bottom of comments with next post available
-				warningStyle = lipgloss.NewStyle().Foreground(colorOrange)
-				footerText = "⚠️  Next ↓ will load next post  •  h/l: switch posts  •  w: open URL  •  Esc: close comments  •  Ctrl+F: search  •  t: toggle sort  •  q: quit"
-				return warningStyle.Render(footerText)
+				return styles.Warning.Render(fmt.Sprintf("⚠️  Next %s will load next post%s%s", primaryKey(k.Down), sep, hints))
 			}
 
 			// Normal comments view (no boundary)
-			return footerStyle.Render("↑↓: scroll comments  •  h/l: switch posts  •  w: open URL  •  Esc: close comments  •  Ctrl+F: search  •  t: toggle sort  •  q: quit")
+			return styles.Footer.Render(joinHints(sep, keyHint("scroll comments", k.Up, k.Down), hints))
 		}
-		return footerStyle.Render("↑↓: scroll details  •  h/l: switch posts  •  w: open URL  •  Esc/Tab: back to list  •  c: view comments  •  t: toggle sort  •  q: quit")
+		return styles.Footer.Render(joinHints(sep,
+			keyHint("scroll details", k.Up, k.Down),
+			keyHint("switch posts", k.PrevPost, k.NextPost),
+			keyHint("open URL", k.OpenURL),
+			keyHint("back to list", k.Back),
+			keyHint("view comments", k.Comments),
+			keyHint("reader", k.Reader),
+			keyHint("export", k.Export),
+			keyHint("toggle sort", k.ToggleSort),
+			keyHint("quit", k.Quit)))
 	}
 
 	status := "no posts"
@@ -1245,7 +2121,19 @@ func (m Model) renderFooter() string {
 		sortLabel = "🆕 New"
 	}
 
-	return footerStyle.Render(fmt.Sprintf("Post %s [%s]  •  Enter: view  •  1-9: subreddit  •  t: toggle sort  •  F5: refresh  •  q: quit", status, sortLabel))
+	shortcuts := ""
+	if len(m.shortcuts()) > 0 {
+		shortcuts = "1-9: subreddit"
+	}
+	return styles.Footer.Render(joinHints(sep,
+		fmt.Sprintf("Post %s [%s]", status, sortLabel),
+		keyHint("view", k.Open),
+		keyHint("new post", k.NewPost),
+		keyHint("home", k.Home),
+		shortcuts,
+		keyHint("toggle sort", k.ToggleSort),
+		keyHint("refresh", k.Refresh),
+		keyHint("quit", k.Quit)))
 }
 
 // ============= Utilities =============
@@ -1310,8 +2198,69 @@ func main() {
 	if err := loadConfig(); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: could not load config.json: %v\n", err)
 	}
+	applySavedSplit()
+	t, err := loadTheme()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	applyTheme(t)
+	activeGraphics = detectGraphicsProtocol(appConfig.TUI.ImagePreview)
+	if noColor && activeGraphics == graphicsHalfBlock {
+		// Half-block previews are nothing but color
+		activeGraphics = graphicsNone
+	}
+
+	if !validCommentSort(appConfig.TUI.CommentSort) {
+		fmt.Fprintf(os.Stderr, "Warning: unknown comment_sort %q; using best\n", appConfig.TUI.CommentSort)
+		appConfig.TUI.CommentSort = "best"
+	}
+	if !validExportFormat(appConfig.TUI.ExportFormat) {
+		fmt.Fprintf(os.Stderr, "Warning: unknown export_format %q; using markdown\n", appConfig.TUI.ExportFormat)
+		appConfig.TUI.ExportFormat = "markdown"
+	}
+	if n := appConfig.TUI.FollowSeconds; n < minFollowSeconds {
+		fmt.Fprintf(os.Stderr, "Warning: follow_seconds %d is too short; using %d\n", n, minFollowSeconds)
+		appConfig.TUI.FollowSeconds = minFollowSeconds
+	}
+	if n := appConfig.TUI.AutoRefreshSeconds; n > 0 && n < minRefreshSeconds {
+		fmt.Fprintf(os.Stderr, "Warning: auto_refresh_seconds %d is too short; using %d\n", n, minRefreshSeconds)
+		appConfig.TUI.AutoRefreshSeconds = minRefreshSeconds
+	}
+	for _, problem := range compileWatchRules() {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", problem)
+	}
+	if !validHomeFeed(appConfig.TUI.HomeFeed) {
+		fmt.Fprintf(os.Stderr, "Warning: unknown home_feed %q; using auto\n", appConfig.TUI.HomeFeed)
+		appConfig.TUI.HomeFeed = homeFeedAuto
+	}
+
+	if _, err := loadSession(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not load saved login: %v\n", err)
+	}
+
+	// redditview list|search|comments ... prints and exits without the UI
+	if status, ok := runCLI(os.Args[1:]); ok {
+		os.Exit(status)
+	}
+
+	keys, err := newKeyMap(appConfig.Keybindings)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; using default keybindings\n", err)
+		keys = DefaultKeyMap()
+	}
+
+	m := initialModel(keys)
+	if len(os.Args) > 1 {
+		// redditview <permalink | redd.it link | post ID>
+		ref, err := parsePostRef(os.Args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+		m.startPost = &ref
+	}
 
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
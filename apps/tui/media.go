@@ -0,0 +1,604 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ============= Media Detection =============
+
+const (
+	mediaPreviewMinWidth = 320
+	maxMediaDownload     = 20 << 20 // 20 MiB
+	maxMediaPixels       = 40e6     // decoded, about 160 MB of RGBA
+)
+
+var imageExtensions = map[string]bool{
+	".jpg": true, ".jpeg": true, ".png": true, ".gif": true,
+}
+
+// MediaImages returns the URLs of the images attached to a post, best
+// suited for a terminal preview first. Galleries return one URL per item.
+func (p RedditPostData) MediaImages() []string {
+	if p.IsGallery && p.GalleryData != nil {
+		urls := make([]string, 0, len(p.GalleryData.Items))
+		for _, item := range p.GalleryData.Items {
+			meta, ok := p.MediaMetadata[item.MediaID]
+			if !ok || meta.Status != "valid" {
+				continue
+			}
+			best := meta.Source.URL
+			for _, res := range meta.Previews {
+				if res.Width >= mediaPreviewMinWidth {
+					best = res.URL
+					break
+				}
+			}
+			if best != "" {
+				urls = append(urls, html.UnescapeString(best))
+			}
+		}
+		return urls
+	}
+
+	if p.Preview != nil && len(p.Preview.Images) > 0 {
+		img := p.Preview.Images[0]
+		best := img.Source.URL
+		for _, res := range img.Resolutions {
+			if res.Width >= mediaPreviewMinWidth {
+				best = res.URL
+				break
+			}
+		}
+		if best != "" {
+			return []string{html.UnescapeString(best)}
+		}
+	}
+
+	if direct := directImageURL(p.URL); direct != "" {
+		return []string{direct}
+	}
+	return nil
+}
+
+// HasMedia reports whether the post links to an image, gallery or video.
+func (p RedditPostData) HasMedia() bool {
+	return p.IsVideo || len(p.MediaImages()) > 0
+}
+
+// directImageURL recognises links that point straight at an image, such as
+// i.redd.it uploads and imgur pages, and returns a fetchable image URL.
+func directImageURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	ext := strings.ToLower(path.Ext(u.Path))
+
+	switch {
+	case host == "i.redd.it" && imageExtensions[ext]:
+		return raw
+	case host == "i.imgur.com" && ext == ".gifv":
+		return strings.TrimSuffix(raw, path.Ext(u.Path)) + ".gif"
+	case host == "i.imgur.com" && imageExtensions[ext]:
+		return raw
+	case host == "imgur.com" && ext == "" && !strings.HasPrefix(u.Path, "/a/") && !strings.HasPrefix(u.Path, "/gallery/"):
+		id := strings.Trim(u.Path, "/")
+		if id != "" && !strings.Contains(id, "/") {
+			return "https://i.imgur.com/" + id + ".jpg"
+		}
+	case imageExtensions[ext]:
+		return raw
+	}
+	return ""
+}
+
+// ============= Graphics Protocols =============
+
+type graphicsProtocol string
+
+const (
+	graphicsNone      graphicsProtocol = "off"
+	graphicsHalfBlock graphicsProtocol = "halfblock"
+	graphicsKitty     graphicsProtocol = "kitty"
+	graphicsITerm2    graphicsProtocol = "iterm2"
+	graphicsSixel     graphicsProtocol = "sixel"
+)
+
+// Approximate size of a terminal cell, used when a protocol needs pixels
+const (
+	cellPixelWidth  = 10
+	cellPixelHeight = 20
+)
+
+// kittyClearSeq deletes every kitty image placement on screen
+const kittyClearSeq = "\x1b_Ga=d,d=a\x1b\\"
+
+var activeGraphics = graphicsHalfBlock
+
+// detectGraphicsProtocol picks the image protocol from the configured
+// preference, falling back to sniffing the terminal's environment.
+func detectGraphicsProtocol(pref string) graphicsProtocol {
+	switch graphicsProtocol(strings.ToLower(pref)) {
+	case graphicsNone, graphicsHalfBlock, graphicsKitty, graphicsITerm2, graphicsSixel:
+		return graphicsProtocol(strings.ToLower(pref))
+	}
+
+	term := os.Getenv("TERM")
+	termProgram := os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("TMUX") != "":
+		// Passthrough of graphics escapes is unreliable under tmux
+		return graphicsHalfBlock
+	case os.Getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || termProgram == "ghostty":
+		return graphicsKitty
+	case termProgram == "iTerm.app" || termProgram == "WezTerm" || os.Getenv("LC_TERMINAL") == "iTerm2":
+		return graphicsITerm2
+	case strings.Contains(term, "sixel") || term == "foot" || term == "mlterm":
+		return graphicsSixel
+	}
+	return graphicsHalfBlock
+}
+
+// ============= Download & Cache =============
+
+// Decoded images are large, so only the selected post's is kept; the
+// previews of the few posts visited before it stay rendered, at the size
+// they were last drawn, until the window is resized. Going back to a post
+// whose image was dropped decodes it again from the disk cache.
+
+// maxMediaEntries is how many images the cache remembers, the selected one
+// included.
+const maxMediaEntries = 8
+
+type mediaEntry struct {
+	img     image.Image // nil once the post is no longer selected
+	bounds  image.Rectangle
+	err     error
+	loading bool
+	used    int // the cache's use count when last selected
+}
+
+// renderedPreview is an image rendered for one size and protocol.
+type renderedPreview struct {
+	key string
+	out string
+}
+
+// mediaCache holds images and their rendered previews by URL.
+type mediaCache struct {
+	images   map[string]*mediaEntry
+	rendered map[string]renderedPreview
+	uses     int
+}
+
+func newMediaCache() *mediaCache {
+	return &mediaCache{
+		images:   make(map[string]*mediaEntry),
+		rendered: make(map[string]renderedPreview),
+	}
+}
+
+// keep drops the decoded images of every post but the selected one and
+// forgets the least recently selected images beyond maxMediaEntries.
+func (c *mediaCache) keep(selected string) {
+	if entry, ok := c.images[selected]; ok {
+		c.uses++
+		entry.used = c.uses
+	}
+	for url, entry := range c.images {
+		if url != selected && !entry.loading {
+			entry.img = nil
+		}
+	}
+	for len(c.images) > maxMediaEntries {
+		oldest := ""
+		for url, entry := range c.images {
+			if url != selected && (oldest == "" || entry.used < c.images[oldest].used) {
+				oldest = url
+			}
+		}
+		if oldest == "" {
+			return
+		}
+		delete(c.images, oldest)
+		delete(c.rendered, oldest)
+	}
+}
+
+// resized forgets the rendered previews, which were drawn for the old size.
+func (c *mediaCache) resized() {
+	c.rendered = make(map[string]renderedPreview)
+}
+
+type mediaLoadedMsg struct {
+	url   string
+	img   image.Image
+	error error
+}
+
+func mediaCacheDir() string {
+	if appConfig.TUI.MediaCacheDir != "" {
+		return appConfig.TUI.MediaCacheDir
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "redditview", "media")
+}
+
+// fetchImage returns the image at imageURL, downloading it into the local
+// media cache on first use.
+func fetchImage(imageURL string) (image.Image, error) {
+	sum := sha1.Sum([]byte(imageURL))
+	u, _ := url.Parse(imageURL)
+	ext := ""
+	if u != nil {
+		ext = strings.ToLower(path.Ext(u.Path))
+	}
+	cachePath := filepath.Join(mediaCacheDir(), hex.EncodeToString(sum[:])+ext)
+
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		client := &http.Client{Timeout: time.Duration(appConfig.API.TimeoutSeconds) * time.Second}
+		req, err := http.NewRequest(http.MethodGet, imageURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", "redditview/1.0")
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("image download failed: %s", resp.Status)
+		}
+		// Read one byte past the limit to tell a cut-off download from one
+		// that is exactly the limit; a partial image is neither decoded nor
+		// cached
+		data, err = io.ReadAll(io.LimitReader(resp.Body, maxMediaDownload+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > maxMediaDownload {
+			return nil, fmt.Errorf("image larger than %d MiB", maxMediaDownload>>20)
+		}
+		// Caching is best effort; a read-only cache dir only costs a re-download
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0o755); err == nil {
+			_ = os.WriteFile(cachePath, data, 0o644)
+		}
+	}
+
+	// The header gives the dimensions, so an image that would decode into
+	// an enormous bitmap is turned down before any pixels are allocated
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported image: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > maxMediaPixels {
+		return nil, fmt.Errorf("image too large to preview (%dx%d)", cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported image: %w", err)
+	}
+	return img, nil
+}
+
+func loadMedia(imageURL string) tea.Cmd {
+	return func() tea.Msg {
+		img, err := fetchImage(imageURL)
+		return mediaLoadedMsg{imageURL, img, err}
+	}
+}
+
+// selectedMediaURL is the URL of the selected post's preview image, or ""
+// when the detail view is closed or the post has none.
+func (m Model) selectedMediaURL() string {
+	if activeGraphics == graphicsNone || !m.showDetails || m.list.Index() >= len(m.filteredPosts) {
+		return ""
+	}
+	if urls := m.filteredPosts[m.list.Index()].MediaImages(); len(urls) > 0 {
+		return urls[0]
+	}
+	return ""
+}
+
+// loadSelectedMedia starts loading the preview of the selected post if the
+// detail view is open and the image is not decoded yet, and trims the
+// cache to it.
+func (m Model) loadSelectedMedia() tea.Cmd {
+	url := m.selectedMediaURL()
+	m.media.keep(url)
+	if url == "" {
+		return nil
+	}
+	entry, ok := m.media.images[url]
+	if !ok {
+		entry = &mediaEntry{}
+		m.media.images[url] = entry
+	} else if entry.img != nil || entry.err != nil || entry.loading {
+		return nil
+	}
+	// A dropped image keeps its rendered preview on screen while it loads
+	entry.loading = true
+	return loadMedia(url)
+}
+
+// mediaLoaded stores a downloaded image.
+func (m *Model) mediaLoaded(msg mediaLoadedMsg) {
+	entry := &mediaEntry{img: msg.img, err: msg.error}
+	if old, ok := m.media.images[msg.url]; ok {
+		entry.used = old.used
+	}
+	if msg.img != nil {
+		entry.bounds = msg.img.Bounds()
+	}
+	m.media.images[msg.url] = entry
+	m.media.keep(m.selectedMediaURL())
+}
+
+// ============= Preview Rendering =============
+
+// renderMediaLines returns the lines describing a post's media for the
+// detail panel, including the image preview once it has been downloaded.
+func (m Model) renderMediaLines(post RedditPostData, width int) []string {
+	urls := post.MediaImages()
+	if len(urls) == 0 {
+		if post.IsVideo {
+			return []string{fmt.Sprintf("🎬 Video (press %s to open)", primaryKey(m.keys.OpenURL)), ""}
+		}
+		return nil
+	}
+
+	label := "🖼  Image"
+	if post.IsGallery {
+		label = fmt.Sprintf("🖼  Gallery (%d images)", len(urls))
+	}
+	lines := []string{label}
+
+	if activeGraphics == graphicsNone {
+		return append(lines, "")
+	}
+
+	entry := m.media.images[urls[0]]
+	switch {
+	case entry == nil || entry.bounds.Empty() && entry.err == nil:
+		lines = append(lines, "Loading preview...")
+	case entry.err != nil:
+		lines = append(lines, fmt.Sprintf("Preview unavailable: %v", entry.err))
+	default:
+		preview := m.media.preview(urls[0], entry, width, appConfig.TUI.ImagePreviewRows)
+		if preview == nil {
+			preview = []string{"Loading preview..."}
+		}
+		lines = append(lines, preview...)
+	}
+	return append(lines, "")
+}
+
+// preview renders entry's image into at most maxCols x maxRows cells with
+// the active graphics protocol, memoising the result. It returns nil when
+// the image has been dropped and the preview is not rendered at this size.
+func (c *mediaCache) preview(url string, entry *mediaEntry, maxCols, maxRows int) []string {
+	cols, rows := fitCells(entry.bounds, maxCols, maxRows)
+	if cols <= 0 || rows <= 0 {
+		return nil
+	}
+
+	cacheKey := fmt.Sprintf("%dx%d:%s", cols, rows, activeGraphics)
+	out := c.rendered[url].out
+	if c.rendered[url].key != cacheKey {
+		img := entry.img
+		if img == nil {
+			return nil
+		}
+		switch activeGraphics {
+		case graphicsKitty:
+			out = kittyImage(img, cols, rows)
+		case graphicsITerm2:
+			out = iterm2Image(img, cols, rows)
+		case graphicsSixel:
+			out = sixelImage(resizeImage(img, cols*cellPixelWidth, rows*cellPixelHeight))
+		default:
+			out = halfBlockImage(resizeImage(img, cols, rows*2))
+		}
+		c.rendered[url] = renderedPreview{cacheKey, out}
+	}
+
+	if activeGraphics == graphicsHalfBlock {
+		return strings.Split(out, "\n")
+	}
+	// Pixel protocols draw from the cursor; reserve the rows underneath
+	lines := make([]string, rows)
+	lines[0] = out
+	return lines
+}
+
+// fitCells scales an image's bounds into the cell budget, keeping its aspect
+// ratio given cells roughly twice as tall as they are wide.
+func fitCells(b image.Rectangle, maxCols, maxRows int) (int, int) {
+	w, h := b.Dx(), b.Dy()
+	if w <= 0 || h <= 0 || maxCols <= 0 || maxRows <= 0 {
+		return 0, 0
+	}
+	cols := min(maxCols, w)
+	rows := (cols*h + w) / (2 * w)
+	if rows > maxRows {
+		rows = maxRows
+		cols = max(1, rows*2*w/h)
+	}
+	return cols, max(1, rows)
+}
+
+// resizeImage box-samples src down (or nearest-samples up) to w x h.
+func resizeImage(src image.Image, w, h int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	b := src.Bounds()
+	for y := 0; y < h; y++ {
+		y0 := b.Min.Y + y*b.Dy()/h
+		y1 := max(y0+1, b.Min.Y+(y+1)*b.Dy()/h)
+		for x := 0; x < w; x++ {
+			x0 := b.Min.X + x*b.Dx()/w
+			x1 := max(x0+1, b.Min.X+(x+1)*b.Dx()/w)
+			var r, g, bl, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a, n = r+cr, g+cg, bl+cb, a+ca, n+1
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{uint8(r / n >> 8), uint8(g / n >> 8), uint8(bl / n >> 8), uint8(a / n >> 8)})
+		}
+	}
+	return dst
+}
+
+// halfBlockImage draws two pixels per cell with the upper half block,
+// the foreground colouring the top pixel and the background the bottom one.
+func halfBlockImage(img *image.RGBA) string {
+	b := img.Bounds()
+	var sb strings.Builder
+	for y := 0; y+1 < b.Dy(); y += 2 {
+		if y > 0 {
+			sb.WriteString("\n")
+		}
+		for x := 0; x < b.Dx(); x++ {
+			top, bottom := img.RGBAAt(x, y), img.RGBAAt(x, y+1)
+			sb.WriteString(lipgloss.NewStyle().
+				Foreground(lipgloss.Color(hexColor(top))).
+				Background(lipgloss.Color(hexColor(bottom))).
+				Render("▀"))
+		}
+	}
+	return sb.String()
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func encodePNG(img image.Image) []byte {
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+// kittyImage transmits img as PNG and places it over cols x rows cells.
+func kittyImage(img image.Image, cols, rows int) string {
+	payload := base64.StdEncoding.EncodeToString(
+		encodePNG(resizeImage(img, cols*cellPixelWidth, rows*cellPixelHeight)))
+
+	var sb strings.Builder
+	sb.WriteString(kittyClearSeq)
+	const chunk = 4096
+	for i := 0; i < len(payload); i += chunk {
+		end := min(i+chunk, len(payload))
+		more := 0
+		if end < len(payload) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&sb, "\x1b_Gf=100,a=T,q=2,c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, payload[i:end])
+		} else {
+			fmt.Fprintf(&sb, "\x1b_Gm=%d;%s\x1b\\", more, payload[i:end])
+		}
+	}
+	return sb.String()
+}
+
+// iterm2Image uses the inline images extension of OSC 1337.
+func iterm2Image(img image.Image, cols, rows int) string {
+	data := encodePNG(resizeImage(img, cols*cellPixelWidth, rows*cellPixelHeight))
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a",
+		len(data), cols, rows, base64.StdEncoding.EncodeToString(data))
+}
+
+// sixelImage encodes img as DEC sixel graphics using a 6x6x6 colour cube.
+func sixelImage(img *image.RGBA) string {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	level := func(v uint8) int { return (int(v)*5 + 127) / 255 }
+	idx := make([]int, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := img.RGBAAt(x, y)
+			idx[y*w+x] = level(c.R)*36 + level(c.G)*6 + level(c.B)
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\x1bPq\"1;1;%d;%d", w, h)
+	for i := 0; i < 216; i++ {
+		fmt.Fprintf(&sb, "#%d;2;%d;%d;%d", i, i/36*20, i/6%6*20, i%6*20)
+	}
+
+	for y0 := 0; y0 < h; y0 += 6 {
+		var used [216]bool
+		for y := y0; y < min(y0+6, h); y++ {
+			for x := 0; x < w; x++ {
+				used[idx[y*w+x]] = true
+			}
+		}
+		for c := 0; c < 216; c++ {
+			if !used[c] {
+				continue
+			}
+			fmt.Fprintf(&sb, "#%d", c)
+			run, last := 0, byte(0)
+			flush := func() {
+				if run > 3 {
+					fmt.Fprintf(&sb, "!%d%c", run, last)
+				} else {
+					sb.WriteString(strings.Repeat(string(last), run))
+				}
+			}
+			for x := 0; x < w; x++ {
+				var bits byte
+				for k := 0; k < 6 && y0+k < h; k++ {
+					if idx[(y0+k)*w+x] == c {
+						bits |= 1 << k
+					}
+				}
+				ch := 63 + bits
+				if run > 0 && ch == last {
+					run++
+					continue
+				}
+				if run > 0 {
+					flush()
+				}
+				run, last = 1, ch
+			}
+			flush()
+			sb.WriteString("$")
+		}
+		sb.WriteString("-")
+	}
+	sb.WriteString("\x1b\\")
+	return sb.String()
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ============= More Comments =============
+
+// Even with a high limit, Reddit leaves parts of a big thread out of the
+// comment listing and puts "more" stubs in their place, holding the IDs of
+// the comments left out. Logged in, those are loaded through
+// /api/morechildren; the proxy used when not logged in has no route for it,
+// so the comments behind the stubs are only counted.
+
+// moreStub is a "more" placeholder from a comment listing.
+type moreStub struct {
+	parentID string   // fullname of the post or comment it stands under
+	ids      []string // the comments it can load, empty for "continue this thread"
+	count    int      // how many comments it stands for, replies included
+}
+
+// moreChildrenBatch is how many IDs /api/morechildren takes per request,
+// and maxMoreRequests bounds how many requests one thread may make.
+const (
+	moreChildrenBatch = 100
+	maxMoreRequests   = 20
+)
+
+// FetchWholeThread loads a post like FetchThread and then the comments its
+// listing left out. It returns how many comments are still missing.
+func (c *APIClient) FetchWholeThread(subreddit, postID, sort string) (RedditPostData, []*Comment, int, error) {
+	post, listing, err := c.fetchPostListing(c.threadURL(subreddit, postID, sort), postID)
+	if err != nil {
+		return RedditPostData{}, nil, 0, err
+	}
+	comments := parseCommentChildren(listing, 0, true)
+	comments, missing := c.expandMore(postID, sort, comments, collectMore(listing))
+	return post, comments, missing, nil
+}
+
+// collectMore finds the "more" stubs in a raw comment listing and in the
+// replies of its comments.
+func collectMore(listing map[string]interface{}) []moreStub {
+	children, _ := listing["children"].([]interface{})
+	var stubs []moreStub
+	for _, child := range children {
+		thing, _ := child.(map[string]interface{})
+		data, _ := thing["data"].(map[string]interface{})
+		switch thing["kind"] {
+		case "more":
+			stubs = append(stubs, newMoreStub(data))
+		case "t1":
+			if replies, ok := data["replies"].(map[string]interface{}); ok {
+				if repliesData, ok := replies["data"].(map[string]interface{}); ok {
+					stubs = append(stubs, collectMore(repliesData)...)
+				}
+			}
+		}
+	}
+	return stubs
+}
+
+func newMoreStub(data map[string]interface{}) moreStub {
+	stub := moreStub{parentID: toString(data["parent_id"]), count: toInt(data["count"])}
+	ids, _ := data["children"].([]interface{})
+	for _, id := range ids {
+		if s := toString(id); s != "" {
+			stub.ids = append(stub.ids, s)
+		}
+	}
+	return stub
+}
+
+// expandMore loads the comments behind stubs and adds them under their
+// parents. Stubs that cannot be loaded are counted in the missing total.
+func (c *APIClient) expandMore(postID, sort string, comments []*Comment, stubs []moreStub) ([]*Comment, int) {
+	byID := make(map[string]*Comment)
+	var index func(comments []*Comment)
+	index = func(comments []*Comment) {
+		for _, comment := range comments {
+			byID[comment.ID] = comment
+			index(comment.Replies)
+		}
+	}
+	index(comments)
+
+	missing, requests := 0, 0
+	for len(stubs) > 0 {
+		stub := stubs[0]
+		stubs = stubs[1:]
+		if len(stub.ids) == 0 || !c.Authenticated() || requests == maxMoreRequests {
+			missing += max(1, max(stub.count, len(stub.ids)))
+			continue
+		}
+
+		batch := stub.ids[:min(moreChildrenBatch, len(stub.ids))]
+		if rest := stub.ids[len(batch):]; len(rest) > 0 {
+			stubs = append(stubs, moreStub{stub.parentID, rest, max(len(rest), stub.count-len(batch))})
+		}
+		requests++
+		things, err := c.fetchMoreChildren(postID, sort, batch)
+		if err != nil {
+			missing += len(batch)
+			continue
+		}
+
+		for _, thing := range things {
+			data, _ := thing["data"].(map[string]interface{})
+			if thing["kind"] == "more" {
+				stubs = append(stubs, newMoreStub(data))
+				continue
+			}
+			if byID[toString(data["id"])] != nil {
+				continue // already loaded
+			}
+			// Things come parents first, each naming the one it replies to
+			parentID := toString(data["parent_id"])
+			parent, depth := byID[strings.TrimPrefix(parentID, "t1_")], 0
+			if parent != nil {
+				depth = parent.Depth + 1
+			} else if parentID != "t3_"+postID {
+				missing++
+				continue
+			}
+			loaded := parseCommentChildren(map[string]interface{}{"children": []interface{}{thing}}, depth, true)
+			if parent != nil {
+				parent.Replies = append(parent.Replies, loaded...)
+			} else {
+				comments = append(comments, loaded...)
+			}
+			index(loaded)
+		}
+	}
+	return comments, missing
+}
+
+// fetchMoreChildren loads comments by ID through /api/morechildren.
+func (c *APIClient) fetchMoreChildren(postID, sort string, ids []string) ([]map[string]interface{}, error) {
+	q := url.Values{
+		"api_type":       {"json"},
+		"link_id":        {"t3_" + postID},
+		"children":       {strings.Join(ids, ",")},
+		"limit_children": {"false"},
+	}
+	if sort != "" {
+		q.Set("sort", commentSortParam(sort))
+	}
+	resp, err := c.client.Get(c.baseURL + "/api/morechildren?" + q.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("more comments: %s", resp.Status)
+	}
+
+	var result struct {
+		JSON struct {
+			Errors [][]interface{} `json:"errors"`
+			Data   struct {
+				Things []map[string]interface{} `json:"things"`
+			} `json:"data"`
+		} `json:"json"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse Reddit API response: %w", err)
+	}
+	if len(result.JSON.Errors) > 0 {
+		return nil, fmt.Errorf("more comments: %v", result.JSON.Errors[0])
+	}
+	return result.JSON.Data.Things, nil
+}
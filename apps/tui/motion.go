@@ -0,0 +1,454 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ============= Motions =============
+
+// A motion moves within the active pane: the post list, the detail panel or
+// the comments panel. Motions take an optional count ("5j") and may be bound
+// to key sequences ("g g"); both are collected here before handleKeyPress
+// sees the key.
+
+const (
+	// motionTimeout is how long a digit that is also a subreddit shortcut
+	// waits for a motion before it switches subreddit.
+	motionTimeout = 600 * time.Millisecond
+	maxCount      = 9999
+)
+
+type pane int
+
+const (
+	paneList pane = iota
+	paneDetails
+	paneComments
+	paneReader
+	paneHelp
+)
+
+type motionState struct {
+	count int
+	seq   []string
+	id    int // bumped on every key so stale timeouts are ignored
+}
+
+// pending renders the count and partial sequence typed so far, e.g. "5g".
+func (s motionState) pending() string {
+	var sb strings.Builder
+	if s.count > 0 {
+		sb.WriteString(strconv.Itoa(s.count))
+	}
+	for _, k := range s.seq {
+		sb.WriteString(keyName(k))
+	}
+	return sb.String()
+}
+
+type motionTimeoutMsg struct {
+	id int
+}
+
+// searchMatch is the line n/N last jumped to in the detail or comments pane.
+type searchMatch struct {
+	pane pane
+	line int
+}
+
+var noMatch = searchMatch{line: -1}
+
+type motion struct {
+	binding func(KeyMap) key.Binding
+	// run applies the motion; counted reports whether a count was typed
+	run func(m *Model, count int, counted bool) tea.Cmd
+	// inPane motions only move within the active pane, so they also scroll
+	// the help overlay; the others act on the posts behind it
+	inPane bool
+}
+
+var motions = []motion{
+	{func(k KeyMap) key.Binding { return k.Up }, func(m *Model, n int, counted bool) tea.Cmd {
+		if m.activePane() == paneComments && !counted && m.commentsScrollY == 0 {
+			// At top of comments - navigate to previous post
+			if m.list.Index() > 0 {
+				m.selectPost(m.list.Index() - 1)
+				m.showComments = false // Auto-close comments for new post
+			}
+			return nil
+		}
+		m.scrollTo(m.position() - n)
+		return nil
+	}, true},
+	{func(k KeyMap) key.Binding { return k.Down }, func(m *Model, n int, counted bool) tea.Cmd {
+		if m.activePane() == paneComments && !counted && m.commentsScrollY >= m.commentsMaxScroll {
+			// At bottom of comments - navigate to next post
+			if m.list.Index() < len(m.filteredPosts)-1 {
+				m.selectPost(m.list.Index() + 1)
+				m.showComments = false // Auto-close comments for new post
+			}
+			return nil
+		}
+		m.scrollTo(m.position() + n)
+		return nil
+	}, true},
+	{func(k KeyMap) key.Binding { return k.PrevPost }, func(m *Model, n int, _ bool) tea.Cmd {
+		m.selectPost(m.list.Index() - n)
+		return nil
+	}, false},
+	{func(k KeyMap) key.Binding { return k.NextPost }, func(m *Model, n int, _ bool) tea.Cmd {
+		m.selectPost(m.list.Index() + n)
+		return nil
+	}, false},
+	{func(k KeyMap) key.Binding { return k.PageUp }, func(m *Model, n int, _ bool) tea.Cmd {
+		m.scrollTo(m.position() - n*m.pageSize())
+		return nil
+	}, true},
+	{func(k KeyMap) key.Binding { return k.PageDown }, func(m *Model, n int, _ bool) tea.Cmd {
+		m.scrollTo(m.position() + n*m.pageSize())
+		return nil
+	}, true},
+	{func(k KeyMap) key.Binding { return k.HalfPageUp }, func(m *Model, n int, _ bool) tea.Cmd {
+		m.scrollTo(m.position() - n*max(1, m.pageSize()/2))
+		return nil
+	}, true},
+	{func(k KeyMap) key.Binding { return k.HalfPageDown }, func(m *Model, n int, _ bool) tea.Cmd {
+		m.scrollTo(m.position() + n*max(1, m.pageSize()/2))
+		return nil
+	}, true},
+	{func(k KeyMap) key.Binding { return k.Top }, func(m *Model, n int, counted bool) tea.Cmd {
+		// With a count, go to that post or line like vim's 5gg
+		if counted {
+			m.scrollTo(n - 1)
+		} else {
+			m.scrollTo(0)
+		}
+		return nil
+	}, true},
+	{func(k KeyMap) key.Binding { return k.Bottom }, func(m *Model, n int, counted bool) tea.Cmd {
+		if counted {
+			m.scrollTo(n - 1)
+		} else {
+			m.scrollTo(m.maxPosition())
+		}
+		return nil
+	}, true},
+	{func(k KeyMap) key.Binding { return k.Center }, func(m *Model, _ int, _ bool) tea.Cmd {
+		m.center()
+		return nil
+	}, false},
+	{func(k KeyMap) key.Binding { return k.NextMatch }, func(m *Model, n int, _ bool) tea.Cmd {
+		return m.jumpToMatch(n)
+	}, false},
+	{func(k KeyMap) key.Binding { return k.PrevMatch }, func(m *Model, n int, _ bool) tea.Cmd {
+		return m.jumpToMatch(-n)
+	}, false},
+}
+
+// handleMotion consumes counts, partial sequences and motion keys. Keys that
+// are not part of a motion are left for handleKeyPress.
+func (m Model) handleMotion(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	k := msg.String()
+	m.motion.id++
+
+	// Counts: a leading 0 is not a count, and a digit bound to a subreddit
+	// shortcut only becomes one if a motion follows before the timeout
+	if len(k) == 1 && k[0] >= '0' && k[0] <= '9' && len(m.motion.seq) == 0 && (m.motion.count > 0 || k != "0") {
+		_, shortcut := m.shortcuts()[k]
+		fresh := m.motion.count == 0
+		m.motion.count = min(maxCount, m.motion.count*10+int(k[0]-'0'))
+		if fresh && shortcut && m.activePane() != paneHelp {
+			id := m.motion.id
+			return m, tea.Tick(motionTimeout, func(time.Time) tea.Msg {
+				return motionTimeoutMsg{id}
+			}), true
+		}
+		return m, nil, true
+	}
+
+	seq := append(append([]string(nil), m.motion.seq...), k)
+	partial := false
+	for _, mo := range motions {
+		b := mo.binding(m.keys)
+		if !b.Enabled() || (m.activePane() == paneHelp && !mo.inPane) {
+			continue
+		}
+		for _, bk := range b.Keys() {
+			want := keySequence(bk)
+			if len(want) < len(seq) || !equalKeys(want[:len(seq)], seq) {
+				continue
+			}
+			if len(want) > len(seq) {
+				partial = true
+				continue
+			}
+			count, counted := m.motion.count, m.motion.count > 0
+			if !counted {
+				count = 1
+			}
+			m.motion = motionState{id: m.motion.id}
+			cmd := mo.run(&m, count, counted)
+			return m, cmd, true
+		}
+	}
+	if partial {
+		m.motion.seq = seq
+		return m, nil, true
+	}
+
+	// Not a motion: drop whatever was pending and let the key through
+	m.clearMotion()
+	return m, nil, false
+}
+
+// clearMotion drops a pending count or partial sequence. Keys that are not
+// motions call it so a count typed before them does not carry over to the
+// next motion.
+func (m *Model) clearMotion() {
+	m.motion = motionState{id: m.motion.id}
+}
+
+// handleMotionTimeout fires the subreddit shortcut of a lone digit once it is
+// clear no motion is following it.
+func (m Model) handleMotionTimeout(msg motionTimeoutMsg) (Model, tea.Cmd) {
+	if msg.id != m.motion.id || m.motion.count == 0 || len(m.motion.seq) > 0 {
+		return m, nil
+	}
+	digit := strconv.Itoa(m.motion.count)
+	m.motion = motionState{id: m.motion.id + 1}
+	if sub, ok := m.shortcuts()[digit]; ok {
+		m.subreddit = sub
+		m.startLoading()
+		m.searchInput.Reset()
+		m.searching = false
+		return m, m.loadPosts(sub, m.sort)
+	}
+	return m, nil
+}
+
+func equalKeys(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
+
+// ============= Pane Positions =============
+
+func (m Model) activePane() pane {
+	switch {
+	case m.showHelp:
+		return paneHelp
+	case m.showDetails && m.reading:
+		return paneReader
+	case m.showDetails && m.showComments:
+		return paneComments
+	case m.showDetails:
+		return paneDetails
+	}
+	return paneList
+}
+
+// position is the selected post in the list, or the first visible line of
+// the detail and comments panels.
+func (m Model) position() int {
+	switch m.activePane() {
+	case paneHelp:
+		return m.helpScrollY
+	case paneReader:
+		return m.reader.YOffset
+	case paneComments:
+		return m.commentsScrollY
+	case paneDetails:
+		return m.detailScrollY
+	}
+	return m.list.Index()
+}
+
+func (m Model) maxPosition() int {
+	switch m.activePane() {
+	case paneHelp:
+		return m.helpMaxScroll()
+	case paneReader:
+		vp := m.readerView()
+		return max(0, vp.TotalLineCount()-vp.Height)
+	case paneComments:
+		return m.commentsMaxScroll
+	case paneDetails:
+		return m.detailMaxScroll()
+	}
+	return max(0, len(m.filteredPosts)-1)
+}
+
+// pageSize is the number of posts or lines one page motion moves.
+func (m Model) pageSize() int {
+	switch m.activePane() {
+	case paneHelp:
+		return m.helpHeight()
+	case paneReader:
+		return m.readerView().Height
+	case paneComments:
+		return max(1, m.detailsHeight()-4)
+	case paneDetails:
+		if m.list.Index() < len(m.filteredPosts) {
+			return m.detailVisibleLines(m.filteredPosts[m.list.Index()])
+		}
+	}
+	return max(1, m.list.Paginator.PerPage)
+}
+
+// scrollTo moves the active pane to pos, clamped to its range.
+func (m *Model) scrollTo(pos int) {
+	pos = max(0, min(pos, m.maxPosition()))
+	switch m.activePane() {
+	case paneHelp:
+		m.helpScrollY = pos
+	case paneReader:
+		m.reader = m.readerView()
+		m.reader.SetYOffset(pos)
+	case paneComments:
+		m.commentsScrollY = pos
+	case paneDetails:
+		m.detailScrollY = pos
+	default:
+		if len(m.filteredPosts) > 0 {
+			m.list.Select(pos)
+		}
+	}
+}
+
+// selectPost moves the selection to post i, resetting the reader's scroll.
+func (m *Model) selectPost(i int) {
+	if len(m.filteredPosts) == 0 {
+		return
+	}
+	i = max(0, min(i, len(m.filteredPosts)-1))
+	if i == m.list.Index() {
+		return
+	}
+	m.list.Select(i)
+	m.detailScrollY = 0
+	m.commentsScrollY = 0
+	m.reader.YOffset = 0
+	m.match = noMatch
+}
+
+// center scrolls the last search match, or else the focused comment, to the
+// middle of the panel. The list pages rather than scrolls, so it is left alone.
+func (m *Model) center() {
+	p := m.activePane()
+	line := -1
+	if m.match.pane == p {
+		line = m.match.line
+	}
+	if line < 0 && p == paneComments {
+		_, starts := m.commentLines()
+		for _, start := range starts {
+			if start <= m.commentsScrollY {
+				line = start
+			}
+		}
+	}
+	if line < 0 || p == paneList {
+		return
+	}
+	m.scrollTo(line - m.pageSize()/2)
+}
+
+// ============= Search Matches =============
+
+// jumpToMatch moves n matches of the last search forward (or backward when
+// n is negative), wrapping around like vim.
+func (m *Model) jumpToMatch(n int) tea.Cmd {
+	query := strings.ToLower(strings.TrimSpace(m.query))
+	if query == "" {
+		return m.showToast("No search pattern (use " + primaryKey(m.keys.Search) + ")")
+	}
+
+	p := m.activePane()
+	var candidates []string
+	var current int
+	switch p {
+	case paneList:
+		for _, post := range m.filteredPosts {
+			candidates = append(candidates, post.Title+" "+post.Author)
+		}
+		current = m.list.Index()
+	case paneDetails:
+		if m.list.Index() < len(m.filteredPosts) {
+			candidates = m.detailLines(m.filteredPosts[m.list.Index()])
+		}
+		current = m.detailScrollY - 1
+	case paneComments:
+		candidates, _ = m.commentLines()
+		current = m.commentsScrollY - 1
+	case paneReader:
+		candidates, _ = m.readerLines()
+		current = m.reader.YOffset - 1
+	}
+	if m.match.pane == p && m.match.line >= 0 {
+		current = m.match.line
+	}
+
+	var hits []int
+	for i, c := range candidates {
+		if strings.Contains(strings.ToLower(ansi.Strip(c)), query) {
+			hits = append(hits, i)
+		}
+	}
+	if len(hits) == 0 {
+		return m.showToast("Pattern not found: " + m.query)
+	}
+
+	// Step from the nearest hit in the direction of travel
+	var idx int
+	if n > 0 {
+		idx = len(hits) // wraps to the first hit
+		for i, h := range hits {
+			if h > current {
+				idx = i
+				break
+			}
+		}
+		idx += n - 1
+	} else {
+		idx = -1 // wraps to the last hit
+		for i, h := range hits {
+			if h < current {
+				idx = i
+			}
+		}
+		idx += n + 1
+	}
+	idx = ((idx % len(hits)) + len(hits)) % len(hits)
+	line := hits[idx]
+
+	if p == paneList {
+		m.selectPost(line)
+		return nil
+	}
+	m.match = searchMatch{pane: p, line: line}
+	if line < m.position() || line >= m.position()+m.pageSize() {
+		m.scrollTo(line - 2)
+	}
+	return nil
+}
+
+// highlightMatch marks the current search match among a pane's lines.
+func (m Model) highlightMatch(p pane, lines []string) []string {
+	if m.match.pane != p || m.match.line < 0 || m.match.line >= len(lines) {
+		return lines
+	}
+	out := append([]string(nil), lines...)
+	out[m.match.line] = lipgloss.NewStyle().Reverse(true).Render(ansi.Strip(out[m.match.line]))
+	return out
+}
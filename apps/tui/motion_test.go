@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestPendingCountBeforeOtherKeys(t *testing.T) {
+	posts := make([]RedditPostData, 20)
+	for i := range posts {
+		posts[i] = RedditPostData{ID: string(rune('a' + i)), Title: "post"}
+	}
+	tests := []struct {
+		name      string
+		keys      string
+		wantIndex int
+		wantAbs   bool
+	}{
+		{"count and motion", "5j", 5, false},
+		{"count then other key", "5a", 0, true},
+		{"prefix then other key", "ga", 0, true},
+		{"count before copy is dropped", "3yqj", 1, false},
+		{"count before help is dropped", "3?\x1bj", 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t)
+			m = update(t, m, postsLoadedMsg{posts: posts})
+			m = typeKeys(t, m, tt.keys)
+			if got := m.list.Index(); got != tt.wantIndex {
+				t.Errorf("index = %d, want %d", got, tt.wantIndex)
+			}
+			if m.absoluteTime != tt.wantAbs {
+				t.Errorf("absoluteTime = %v, want %v", m.absoluteTime, tt.wantAbs)
+			}
+			if p := m.motion.pending(); p != "" {
+				t.Errorf("still pending %q", p)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ============= Mouse =============
+
+const (
+	doubleClickInterval = 400 * time.Millisecond
+	wheelLines          = 3
+)
+
+var urlPattern = regexp.MustCompile(`https?://[^\s<>()\[\]"']+`)
+
+// region is the part of the screen a mouse event landed on.
+type region int
+
+const (
+	regionNone region = iota
+	regionList
+	regionSeparator
+	regionPanel
+)
+
+// regionAt maps a screen cell to the part of the layout drawn there, along
+// with the row's offset inside that part.
+func (m Model) regionAt(x, y int) (region, int) {
+	lay := m.layout()
+	switch {
+	case lay.list.contains(x, y):
+		return regionList, y - lay.list.y
+	case lay.separator.contains(x, y):
+		return regionSeparator, 0
+	case lay.panel.contains(x, y):
+		return regionPanel, y - lay.panel.y
+	}
+	return regionNone, 0
+}
+
+// renderedList returns the list sized the way View draws it.
+func (m Model) renderedList() list.Model {
+	l := m.list
+	if r := m.layout().list; r.h > 0 {
+		l.SetSize(r.w, r.h)
+	}
+	return l
+}
+
+// postAt returns the index of the post drawn at row of the list, if any.
+func (m Model) postAt(row int) (int, bool) {
+	l := m.renderedList()
+	if l.ShowTitle() {
+		row -= lipgloss.Height(l.Styles.TitleBar.Render(l.Styles.Title.Render(l.Title)))
+	}
+	if l.ShowStatusBar() {
+		row -= lipgloss.Height(l.Styles.StatusBar.Render(" "))
+	}
+	if row < 0 {
+		return 0, false
+	}
+
+	d := newListDelegate()
+	step := d.Height() + d.Spacing()
+	if row%step >= d.Height() {
+		return 0, false // the gap between two posts
+	}
+	start, end := l.Paginator.GetSliceBounds(len(l.VisibleItems()))
+	i := start + row/step
+	if i >= end {
+		return 0, false
+	}
+	return i, true
+}
+
+func (m Model) handleMouse(msg tea.MouseMsg) (Model, tea.Cmd) {
+	if m.loading || m.error != "" || m.searching || m.selectingSub || m.goingTo || m.composing || m.posting || m.showInbox || m.showAlerts {
+		return m, nil
+	}
+	if m.showHelp {
+		maxScroll := max(0, len(m.helpLines(m.windowWidth-6))-m.helpHeight())
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.helpScrollY = max(0, m.helpScrollY-wheelLines)
+		case tea.MouseButtonWheelDown:
+			m.helpScrollY = min(maxScroll, m.helpScrollY+wheelLines)
+		}
+		return m, nil
+	}
+
+	// Dragging the separator follows the pointer until the button is released
+	if m.dragging {
+		switch msg.Action {
+		case tea.MouseActionRelease:
+			m.dragging = false
+			return m, m.saveSplit()
+		case tea.MouseActionMotion:
+			m.dragSeparator(msg.X, msg.Y)
+		}
+		return m, nil
+	}
+
+	where, row := m.regionAt(msg.X, msg.Y)
+	switch msg.Button {
+	case tea.MouseButtonWheelUp, tea.MouseButtonWheelDown:
+		delta := 1
+		if msg.Button == tea.MouseButtonWheelUp {
+			delta = -1
+		}
+		switch where {
+		case regionList:
+			m.selectPost(m.list.Index() + delta)
+		case regionPanel:
+			m.scrollTo(m.position() + delta*wheelLines)
+		}
+		return m, nil
+
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+		switch where {
+		case regionList:
+			return m.clickPost(row)
+		case regionSeparator:
+			m.dragging = true
+		case regionPanel:
+			return m.clickPanel(row)
+		}
+	}
+	return m, nil
+}
+
+// clickPost selects the post under the pointer; a second click on the same
+// post opens it.
+func (m Model) clickPost(row int) (Model, tea.Cmd) {
+	i, ok := m.postAt(row)
+	if !ok {
+		return m, nil
+	}
+	now := clock.Now()
+	double := i == m.lastClickAt && now.Sub(m.lastClick) < doubleClickInterval
+	m.lastClick, m.lastClickAt = now, i
+
+	m.selectPost(i)
+	if double {
+		m.showDetails = true
+		m.showComments = false
+		m.detailScrollY = 0
+		m.reading = false
+		m.lastClick = time.Time{}
+	}
+	return m, nil
+}
+
+// clickPanel opens the link on the clicked line of the detail or comments
+// panel.
+func (m Model) clickPanel(row int) (Model, tea.Cmd) {
+	var line string
+	if m.reading {
+		// Row 0 is the sticky title
+		lines, _ := m.readerLines()
+		i := m.reader.YOffset + row - 1
+		if row < 1 || i >= len(lines) {
+			return m, nil
+		}
+		line = lines[i]
+	} else if m.showComments {
+		// Lines start below the "💬 Comments" heading and a blank line
+		lines, _ := m.commentLines()
+		i := m.commentsScrollY + row - 2
+		if row < 2 || i >= len(lines) {
+			return m, nil
+		}
+		line = lines[i]
+	} else {
+		if m.list.Index() >= len(m.filteredPosts) {
+			return m, nil
+		}
+		post := m.filteredPosts[m.list.Index()]
+		header := m.detailsHeight() - m.detailVisibleLines(post)
+		lines := m.detailLines(post)
+		i := m.detailScrollY + row - header
+		if row < header || i >= len(lines) {
+			return m, nil
+		}
+		line = lines[i]
+		if strings.HasPrefix(line, "🔗 ") {
+			// The link line may be shortened to fit; open the full URL
+			line = post.URL
+		}
+	}
+
+	link := urlPattern.FindString(ansi.Strip(line))
+	if link == "" {
+		return m, nil
+	}
+	link = strings.TrimRight(link, ".,;:!?")
+	if err := openURL(link); err != nil {
+		return m, m.showToast("Failed to open URL: " + err.Error())
+	}
+	return m, m.showToast("🔗 Opened " + link)
+}
+
+// dragSeparator moves the separator to the pointer.
+func (m *Model) dragSeparator(x, y int) {
+	lay := m.layout()
+	if lay.sideBySide {
+		m.setSplitRatio(float64(x) / float64(max(1, m.windowWidth)))
+		return
+	}
+	rows := max(1, m.windowHeight-chromeRows)
+	m.setSplitRatio(float64(y-contentTop) / float64(rows))
+}
@@ -0,0 +1,74 @@
+package main
+
+// ============= Place =============
+
+// A reload, a new sort or a filter replaces the list under the user. The
+// place they were at is saved first and put back afterwards when the post
+// they had selected is still listed: the selection, the open panels and
+// how far each was scrolled.
+
+type place struct {
+	postID          string
+	details         bool
+	comments        bool
+	reading         bool
+	detailScrollY   int
+	commentsScrollY int
+	readerScrollY   int
+}
+
+// currentPlace records the selected post and the panels open on it.
+func (m Model) currentPlace() place {
+	p := place{
+		details:         m.showDetails,
+		comments:        m.showComments,
+		reading:         m.reading,
+		detailScrollY:   m.detailScrollY,
+		commentsScrollY: m.commentsScrollY,
+		readerScrollY:   m.reader.YOffset,
+	}
+	if i := m.list.Index(); i < len(m.filteredPosts) {
+		p.postID = m.filteredPosts[i].ID
+	}
+	return p
+}
+
+// startLoading saves the user's place and shows the loading screen. The
+// place is restored when the posts arrive.
+func (m *Model) startLoading() {
+	m.place = m.currentPlace()
+	m.loading = true
+}
+
+// selectPostID moves the cursor to the post with the given ID, reporting
+// whether it is listed.
+func (m *Model) selectPostID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for i, post := range m.filteredPosts {
+		if post.ID == id {
+			m.list.Select(i)
+			return true
+		}
+	}
+	return false
+}
+
+// restorePlace selects p's post and reopens its panels at their scroll
+// positions, or reports that the post is no longer listed.
+func (m *Model) restorePlace(p place) bool {
+	if !m.selectPostID(p.postID) {
+		return false
+	}
+	m.showDetails = p.details
+	m.showComments = p.comments
+	m.reading = p.reading
+	m.detailScrollY = p.detailScrollY
+	m.reader.YOffset = p.readerScrollY
+	if m.commentsPostID == p.postID {
+		m.commentsScrollY = p.commentsScrollY
+	}
+	m.relayout()
+	return true
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ============= Reader =============
+
+// The reader shows a post's body followed by its comment threads in one
+// scrolling viewport, below a title line that stays put.
+
+// readerLines lays out the reader's scrolling content: badges and metadata,
+// the post body, then the comments. It also returns the line the comments
+// heading is on; the first comment starts two lines below it.
+func (m Model) readerLines() ([]string, int) {
+	if m.list.Index() >= len(m.filteredPosts) {
+		return nil, 0
+	}
+	post := m.filteredPosts[m.list.Index()]
+
+	var lines []string
+	if badges := renderBadges(post); badges != "" {
+		lines = append(lines, badges)
+	}
+	lines = append(lines, styles.Meta.Render(m.postMeta(post)), "")
+	lines = append(lines, m.detailLines(post)...)
+	lines = append(lines, "", styles.Separator.Render(strings.Repeat("─", max(1, m.panelWidth()-2))))
+
+	commentsAt := len(lines)
+	comments, _ := m.commentLines()
+	switch {
+	case len(comments) > 0:
+		heading := styles.Focused.Render(fmt.Sprintf("💬 Comments (%s)", formatNum(post.Comments)))
+		lines = append(lines, heading+styles.Meta.Render("  "+m.commentSortHint()), "")
+		lines = append(lines, comments...)
+	case m.commentsLoading:
+		lines = append(lines, styles.Focused.Render("💬 Loading comments..."))
+	default:
+		lines = append(lines, styles.Focused.Render("💬 No comments found"))
+	}
+	return lines, commentsAt
+}
+
+// readerView returns the reader's viewport sized to the panel and holding
+// the current content, at the model's scroll position.
+func (m Model) readerView() viewport.Model {
+	lines, _ := m.readerLines()
+	r := m.layout().panel
+
+	vp := m.reader
+	vp.Width = max(1, r.w-2)
+	vp.Height = max(1, r.h-1) // below the sticky title
+	vp.SetContent(strings.Join(m.highlightMatch(paneReader, lines), "\n"))
+	return vp
+}
+
+func (m Model) renderReader(height int) string {
+	if m.list.Index() >= len(m.filteredPosts) {
+		return ""
+	}
+	post := m.filteredPosts[m.list.Index()]
+	vp := m.readerView()
+
+	title := styles.Focused.Render("📄 "+post.Title) +
+		styles.Meta.Render(fmt.Sprintf("  %.0f%%", vp.ScrollPercent()*100))
+
+	return styles.Body.
+		Padding(0, 1).
+		Height(height).
+		Render(title + "\n" + vp.View())
+}
+
+// openReader shows the selected post in the reader, from the top.
+func (m *Model) openReader() {
+	if len(m.filteredPosts) == 0 || m.list.Index() >= len(m.filteredPosts) {
+		return
+	}
+	m.showDetails = true
+	m.showComments = false
+	m.reading = true
+	m.reader.YOffset = 0
+	m.match = noMatch
+}
+
+// jumpReaderSection moves the reader between the post body and the first
+// comment.
+func (m *Model) jumpReaderSection() {
+	_, commentsAt := m.readerLines()
+	if m.reader.YOffset < min(commentsAt, m.maxPosition()) {
+		m.scrollTo(commentsAt)
+	} else {
+		m.scrollTo(0)
+	}
+}
+
+// syncComments fetches the selected post's comments when the comments panel
+// or the reader is open on a post whose comments are not loaded yet.
+func (m *Model) syncComments() tea.Cmd {
+	if !m.showDetails || (!m.showComments && !m.reading) || m.list.Index() >= len(m.filteredPosts) {
+		return nil
+	}
+	post := m.filteredPosts[m.list.Index()]
+	if post.ID == m.commentsPostID {
+		return nil
+	}
+
+	m.commentsPostID = post.ID
+	m.comments = nil
+	m.newComments = nil
+	m.commentsLoading = true
+	m.commentsScrollY = 0
+	subreddit := post.SubName
+	if subreddit == "" {
+		subreddit = m.subreddit
+	}
+	return m.loadComments(subreddit, post.ID)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ============= Auto-refresh =============
+
+// With auto_refresh_seconds set, the listing is fetched again in the
+// background on that interval. Posts not seen before are added to the top
+// and marked ✨ until the cursor reaches them; posts already listed get their
+// new scores and comment counts, and posts that dropped out of the listing
+// are removed. The selection, detail view and comments are left where they
+// are.
+
+// minRefreshSeconds keeps polling well inside Reddit's rate limits.
+const minRefreshSeconds = 15
+
+type refreshTickMsg struct{}
+
+type refreshedMsg struct {
+	subreddit string
+	sort      string
+	posts     []RedditPostData
+	error     error
+}
+
+// scheduleRefresh waits for the next background refresh, if enabled.
+func scheduleRefresh() tea.Cmd {
+	if appConfig.TUI.AutoRefreshSeconds <= 0 {
+		return nil
+	}
+	interval := time.Duration(appConfig.TUI.AutoRefreshSeconds) * time.Second
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return refreshTickMsg{}
+	})
+}
+
+// backgroundRefresh fetches the listing being shown, along with the unread
+// count and the subreddits watch rules name, and schedules the next refresh.
+// The listing is not fetched while a load is under way or the list holds
+// search results.
+func (m *Model) backgroundRefresh() tea.Cmd {
+	next := tea.Batch(scheduleRefresh(), m.fetchWatched())
+	if m.loading || m.refreshing || m.showingSearch || len(m.posts) == 0 {
+		return next
+	}
+	m.refreshing = true
+	sub, sort, client := m.subreddit, m.sort, m.client
+	fetch := func() tea.Msg {
+		posts, err := client.FetchPosts(sub, sort, appConfig.TUI.PostsPerPage)
+		return refreshedMsg{sub, sort, posts, err}
+	}
+	return tea.Batch(next, fetch, m.loadUnreadCount())
+}
+
+// mergePosts adds posts that are not listed yet to the top, marking them
+// new, updates the ones still listed in place and drops the rest, except
+// the selected post. The cursor stays on the post it was on.
+func (m *Model) mergePosts(fetched []RedditPostData) {
+	byID := make(map[string]RedditPostData, len(fetched))
+	for _, post := range fetched {
+		byID[post.ID] = post
+	}
+	selected := ""
+	if i := m.list.Index(); i < len(m.filteredPosts) {
+		selected = m.filteredPosts[i].ID
+	}
+
+	// A new slice, since posts and filteredPosts may share an array
+	kept := make([]RedditPostData, 0, len(fetched)+1)
+	known := make(map[string]bool, len(m.posts))
+	for _, post := range m.posts {
+		if fresh, ok := byID[post.ID]; ok {
+			kept = append(kept, fresh)
+		} else if post.ID == selected {
+			kept = append(kept, post)
+		} else {
+			delete(m.newPosts, post.ID)
+			continue
+		}
+		known[post.ID] = true
+	}
+	var added []RedditPostData
+	for _, post := range fetched {
+		if known[post.ID] {
+			continue
+		}
+		added = append(added, post)
+		if m.newPosts == nil {
+			m.newPosts = make(map[string]bool)
+		}
+		m.newPosts[post.ID] = true
+	}
+	m.posts = append(added, kept...)
+
+	// The query only filters the list while it is being typed; after that
+	// it is just the n/N pattern
+	query := ""
+	if m.searching {
+		query = m.query
+	}
+	m.filterPosts(query)
+}
+
+// markSeen clears the new mark of the post under the cursor.
+func (m *Model) markSeen() {
+	i := m.list.Index()
+	if i >= len(m.filteredPosts) || !m.newPosts[m.filteredPosts[i].ID] {
+		return
+	}
+	delete(m.newPosts, m.filteredPosts[i].ID)
+	m.list.SetItem(i, m.postItem(m.filteredPosts[i]))
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newTestModel(t *testing.T) Model {
+	t.Helper()
+	keys, err := newKeyMap(nil)
+	if err != nil {
+		t.Fatalf("newKeyMap: %v", err)
+	}
+	m := initialModel(keys)
+	m.windowWidth, m.windowHeight = 120, 40
+	m.relayout()
+	return m
+}
+
+// update feeds msg to m and returns the updated model.
+func update(t *testing.T, m Model, msg tea.Msg) Model {
+	t.Helper()
+	next, _ := m.Update(msg)
+	return next.(Model)
+}
+
+// keyMsg is the key press for r, with \x1b standing for Esc.
+func keyMsg(r rune) tea.KeyMsg {
+	if r == '\x1b' {
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+func typeKeys(t *testing.T, m Model, text string) Model {
+	t.Helper()
+	for _, r := range text {
+		m = update(t, m, keyMsg(r))
+	}
+	return m
+}
+
+func postIDs(posts []RedditPostData) []string {
+	ids := make([]string, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+	return ids
+}
+
+func TestRefreshAfterSearchKeepsWholeListing(t *testing.T) {
+	m := newTestModel(t)
+	m = update(t, m, postsLoadedMsg{posts: []RedditPostData{{ID: "a", Title: "apples"}, {ID: "b", Title: "bananas"}}})
+
+	// Search Reddit for "apples", then go back to a subreddit
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyCtrlF})
+	m = typeKeys(t, m, "apples")
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	m = update(t, m, searchResultsMsg{posts: []RedditPostData{{ID: "s", Title: "apples"}}, query: "apples"})
+	m.startLoading()
+	m = update(t, m, postsLoadedMsg{posts: []RedditPostData{{ID: "c", Title: "cherries"}, {ID: "d", Title: "dates"}}})
+
+	m = update(t, m, refreshedMsg{subreddit: m.subreddit, sort: m.sort, posts: []RedditPostData{
+		{ID: "e", Title: "elderberries"}, {ID: "c", Title: "cherries"}, {ID: "d", Title: "dates"},
+	}})
+	got := postIDs(m.filteredPosts)
+	if len(got) != 3 || got[0] != "e" || got[1] != "c" || got[2] != "d" {
+		t.Errorf("listed %v after the refresh, want [e c d]", got)
+	}
+	if len(m.list.Items()) != 3 {
+		t.Errorf("%d list items, want 3", len(m.list.Items()))
+	}
+}
+
+func TestRefreshWhileFilteringKeepsFilter(t *testing.T) {
+	m := newTestModel(t)
+	m = update(t, m, postsLoadedMsg{posts: []RedditPostData{{ID: "a", Title: "apples"}, {ID: "b", Title: "bananas"}}})
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyCtrlF})
+	m = typeKeys(t, m, "apple")
+
+	m = update(t, m, refreshedMsg{subreddit: m.subreddit, sort: m.sort, posts: []RedditPostData{
+		{ID: "c", Title: "apple pie"}, {ID: "a", Title: "apples"}, {ID: "b", Title: "bananas"},
+	}})
+	if got := postIDs(m.filteredPosts); len(got) != 2 || got[0] != "c" || got[1] != "a" {
+		t.Errorf("listed %v while filtering, want [c a]", got)
+	}
+}
@@ -0,0 +1,697 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ============= Post Form =============
+
+// The post form submits a text or link post. When the subreddit is chosen
+// its posting rules and flairs are fetched, and the post is checked against
+// the rules before it is sent, so most rejections are caught locally. A
+// submitted post opens in the detail view.
+
+type submitField int
+
+const (
+	fieldSubreddit submitField = iota
+	fieldKind
+	fieldTitle
+	fieldBody // the text body or the link URL, by kind
+	fieldFlair
+	fieldNSFW
+	fieldSpoiler
+	submitFieldCount
+)
+
+// maxTitleLength is Reddit's limit on every subreddit.
+const maxTitleLength = 300
+
+type linkFlair struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+type postForm struct {
+	field     submitField
+	subreddit textinput.Model
+	title     textinput.Model
+	url       textinput.Model
+	body      textarea.Model
+	link      bool
+	nsfw      bool
+	spoiler   bool
+
+	// Rules and flairs of rulesFor, the subreddit last chosen
+	rulesFor string
+	rules    *submitRules // nil until loaded
+	rulesErr string
+	flairs   []linkFlair
+	flair    int // index into flairs, or -1 for none
+
+	problems []string // why the last send was refused
+	sending  bool
+}
+
+func newPostForm() postForm {
+	sub := textinput.New()
+	sub.Prompt = ""
+	sub.Placeholder = "subreddit"
+	sub.CharLimit = 50
+
+	title := textinput.New()
+	title.Prompt = ""
+	title.Placeholder = "Title"
+	title.CharLimit = maxTitleLength
+
+	link := textinput.New()
+	link.Prompt = ""
+	link.Placeholder = "https://..."
+	link.CharLimit = 2000
+
+	body := textarea.New()
+	body.Placeholder = "Text (markdown, optional)..."
+	body.ShowLineNumbers = false
+	body.CharLimit = 40000
+	body.MaxHeight = 0
+
+	return postForm{subreddit: sub, title: title, url: link, body: body, flair: -1}
+}
+
+// sub is the chosen subreddit without any "r/" prefix.
+func (f postForm) sub() string {
+	s := strings.TrimSpace(f.subreddit.Value())
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "/"), "r/")
+	return strings.Trim(s, "/")
+}
+
+func (f postForm) empty() bool {
+	return strings.TrimSpace(f.title.Value()) == "" &&
+		strings.TrimSpace(f.body.Value()) == "" &&
+		strings.TrimSpace(f.url.Value()) == ""
+}
+
+// openPostForm opens the post form, keeping an earlier draft. The subreddit
+// being browsed is filled in when the form has none.
+func (m *Model) openPostForm() tea.Cmd {
+	if !m.client.Authenticated() {
+		return m.showToast("⚠ Log in to post (redditview login)")
+	}
+	if m.form.sub() == "" && m.subreddit != frontPage && !strings.ContainsAny(m.subreddit, "+") &&
+		m.subreddit != "all" && m.subreddit != "popular" {
+		m.form.subreddit.SetValue(m.subreddit)
+	}
+	m.posting = true
+	m.form.problems = nil
+	m.sizePostForm()
+
+	field := fieldTitle
+	if m.form.sub() == "" {
+		field = fieldSubreddit
+	}
+	m.form.field = -1 // force a focus change
+	return tea.Batch(m.focusField(field), m.loadSubmitRules())
+}
+
+// focusField moves to field, wrapping at either end, and loads the rules
+// when the subreddit has just been changed.
+func (m *Model) focusField(field submitField) tea.Cmd {
+	field = (field + submitFieldCount) % submitFieldCount
+	var cmds []tea.Cmd
+	if m.form.field == fieldSubreddit && field != fieldSubreddit {
+		cmds = append(cmds, m.loadSubmitRules())
+	}
+	m.form.field = field
+	m.form.subreddit.Blur()
+	m.form.title.Blur()
+	m.form.url.Blur()
+	m.form.body.Blur()
+	switch field {
+	case fieldSubreddit:
+		cmds = append(cmds, m.form.subreddit.Focus())
+	case fieldTitle:
+		cmds = append(cmds, m.form.title.Focus())
+	case fieldBody:
+		if m.form.link {
+			cmds = append(cmds, m.form.url.Focus())
+		} else {
+			cmds = append(cmds, m.form.body.Focus())
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+type submitRulesMsg struct {
+	subreddit string
+	rules     submitRules
+	flairs    []linkFlair
+	error     error
+}
+
+type postSubmittedMsg struct {
+	subreddit string
+	id        string
+	error     error
+}
+
+// loadSubmitRules fetches the chosen subreddit's rules and flairs unless
+// they are already loaded.
+func (m *Model) loadSubmitRules() tea.Cmd {
+	sub := m.form.sub()
+	if sub == "" || strings.EqualFold(sub, m.form.rulesFor) {
+		return nil
+	}
+	m.form.rulesFor = sub
+	m.form.rules = nil
+	m.form.rulesErr = ""
+	m.form.flairs = nil
+	m.form.flair = -1
+	client := m.client
+	return func() tea.Msg {
+		rules, flairs, err := client.SubmitRules(sub)
+		return submitRulesMsg{sub, rules, flairs, err}
+	}
+}
+
+// setRules records loaded rules if the subreddit is still the chosen one.
+func (f *postForm) setRules(msg submitRulesMsg) {
+	if msg.subreddit != f.rulesFor {
+		return
+	}
+	if msg.error != nil {
+		f.rulesErr = msg.error.Error()
+		f.rules = &submitRules{}
+		return
+	}
+	f.rules = &msg.rules
+	f.flairs = msg.flairs
+	switch msg.rules.SubmissionType {
+	case "self":
+		f.link = false
+	case "link":
+		f.link = true
+	}
+}
+
+func (m *Model) sizePostForm() {
+	width := max(20, m.windowWidth-2) - formLabelWidth
+	m.form.subreddit.Width = width - 3
+	m.form.title.Width = width - 9 // room for the length count
+	m.form.url.Width = width - 1
+	m.form.body.SetWidth(width)
+	// Every row but the body is one line: heading, blank, subreddit, type,
+	// title, flair, NSFW, spoiler, blank, rules and problems
+	m.form.body.SetHeight(max(3, m.composerRows()-11))
+}
+
+// handlePostFormKey drives the post form: send, cancel and moving between
+// fields, with toggles and choices on their own fields and everything else
+// typed into the focused input.
+func (m Model) handlePostFormKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	f := &m.form
+	switch {
+	case key.Matches(msg, m.keys.Cancel):
+		m.posting = false
+		f.subreddit.Blur()
+		f.title.Blur()
+		f.url.Blur()
+		f.body.Blur()
+		if f.empty() {
+			return m, nil
+		}
+		return m, m.showToast("Post kept as a draft")
+	case key.Matches(msg, m.keys.Submit):
+		if f.sending {
+			return m, nil
+		}
+		f.problems = f.validate()
+		if len(f.problems) > 0 {
+			return m, nil
+		}
+		f.sending = true
+		p, client := f.newPost(), m.client
+		send := func() tea.Msg {
+			id, err := client.Submit(p)
+			return postSubmittedMsg{p.subreddit, id, err}
+		}
+		return m, tea.Batch(m.showToast("📝 Posting to r/"+p.subreddit+"..."), send)
+	case key.Matches(msg, m.keys.NextField):
+		cmd := m.focusField(f.field + 1)
+		return m, cmd
+	case key.Matches(msg, m.keys.PrevField):
+		cmd := m.focusField(f.field - 1)
+		return m, cmd
+	}
+
+	toggle := key.Matches(msg, m.keys.Toggle, m.keys.Confirm)
+	var cmd tea.Cmd
+	switch f.field {
+	case fieldKind:
+		if toggle || msg.String() == "left" || msg.String() == "right" {
+			f.link = !f.link
+		}
+	case fieldFlair:
+		if n := len(f.flairs); n > 0 {
+			// -1 (no flair) is part of the cycle
+			switch {
+			case msg.String() == "left":
+				f.flair = (f.flair+1+n)%(n+1) - 1
+			case toggle || msg.String() == "right":
+				f.flair = (f.flair+2)%(n+1) - 1
+			}
+		}
+	case fieldNSFW:
+		if toggle {
+			f.nsfw = !f.nsfw
+		}
+	case fieldSpoiler:
+		if toggle {
+			f.spoiler = !f.spoiler
+		}
+	case fieldSubreddit, fieldTitle:
+		if key.Matches(msg, m.keys.Confirm) {
+			cmd = m.focusField(f.field + 1)
+		} else if f.field == fieldSubreddit {
+			f.subreddit, cmd = f.subreddit.Update(msg)
+		} else {
+			f.title, cmd = f.title.Update(msg)
+		}
+	case fieldBody:
+		if f.link {
+			if key.Matches(msg, m.keys.Confirm) {
+				cmd = m.focusField(f.field + 1)
+			} else {
+				f.url, cmd = f.url.Update(msg)
+			}
+		} else {
+			f.body, cmd = f.body.Update(msg)
+		}
+	}
+	return m, cmd
+}
+
+// updateFocusedInput passes non-key messages such as cursor blinks to the
+// focused input.
+func (f *postForm) updateFocusedInput(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	switch {
+	case f.field == fieldSubreddit:
+		f.subreddit, cmd = f.subreddit.Update(msg)
+	case f.field == fieldTitle:
+		f.title, cmd = f.title.Update(msg)
+	case f.field == fieldBody && f.link:
+		f.url, cmd = f.url.Update(msg)
+	case f.field == fieldBody:
+		f.body, cmd = f.body.Update(msg)
+	}
+	return cmd
+}
+
+// newPost is the form's post as it will be sent.
+func (f postForm) newPost() newPost {
+	p := newPost{
+		subreddit: f.sub(),
+		title:     strings.TrimSpace(f.title.Value()),
+		link:      f.link,
+		nsfw:      f.nsfw,
+		spoiler:   f.spoiler,
+	}
+	if f.link {
+		p.url = strings.TrimSpace(f.url.Value())
+	} else {
+		p.text = strings.TrimSpace(f.body.Value())
+	}
+	if f.flair >= 0 && f.flair < len(f.flairs) {
+		p.flairID, p.flairText = f.flairs[f.flair].ID, f.flairs[f.flair].Text
+	}
+	return p
+}
+
+// ============= Post Form View =============
+
+const formLabelWidth = 12 // "▸ " and a padded label
+
+func (m Model) renderPostForm() string {
+	f := m.form
+	heading := "📝 New post"
+	if sub := f.sub(); sub != "" {
+		heading += " to r/" + sub
+	}
+	if f.sending {
+		heading += "  (sending...)"
+	}
+	lines := []string{styles.Focused.Render(truncateTitle(heading, m.windowWidth-2)), ""}
+
+	row := func(field submitField, label, value string) {
+		marker, style := "  ", styles.Meta
+		if f.field == field {
+			marker, style = "▸ ", styles.Focused
+		}
+		label = style.Width(formLabelWidth).Render(marker + label)
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, label, value))
+	}
+	choice := func(label string, on bool) string {
+		if on {
+			return "◉ " + label
+		}
+		return "○ " + label
+	}
+	check := func(on bool) string {
+		if on {
+			return "[x]"
+		}
+		return "[ ]"
+	}
+
+	row(fieldSubreddit, "Subreddit", "r/"+f.subreddit.View())
+	row(fieldKind, "Type", choice("Text", !f.link)+"   "+choice("Link", f.link))
+	count := fmt.Sprintf("  %d/%d", utf8.RuneCountInString(f.title.Value()), f.titleMax())
+	row(fieldTitle, "Title", f.title.View()+styles.Meta.Render(count))
+	if f.link {
+		row(fieldBody, "URL", f.url.View())
+	} else {
+		row(fieldBody, "Body", f.body.View())
+	}
+
+	flair := "none"
+	switch {
+	case f.flair >= 0 && f.flair < len(f.flairs):
+		flair = f.flairs[f.flair].Text
+	case len(f.flairs) == 0:
+		flair = styles.Meta.Render("none available")
+	}
+	if len(f.flairs) > 0 {
+		flair = "‹ " + flair + " ›" + styles.Meta.Render(fmt.Sprintf("  %d to choose from", len(f.flairs)))
+	}
+	row(fieldFlair, "Flair", flair)
+	row(fieldNSFW, "NSFW", check(f.nsfw))
+	row(fieldSpoiler, "Spoiler", check(f.spoiler))
+	lines = append(lines, "")
+
+	width := max(20, m.windowWidth-4)
+	switch {
+	case f.rulesFor == "":
+		lines = append(lines, styles.Meta.Render("Choose a subreddit to see its posting rules"))
+	case f.rulesErr != "":
+		lines = append(lines, styles.Warning.Render(truncateTitle("⚠ r/"+f.rulesFor+": "+f.rulesErr, width)))
+	case f.rules == nil:
+		lines = append(lines, styles.Meta.Render("Loading rules for r/"+f.rulesFor+"..."))
+	default:
+		rules := "Rules: " + strings.Join(f.rules.summary(), " · ")
+		if len(f.rules.summary()) == 0 {
+			rules = "Rules: no posting requirements"
+		}
+		lines = append(lines, styles.Meta.Render(truncateTitle(rules, width)))
+	}
+	if len(f.problems) > 0 {
+		problem := "⚠ " + f.problems[0]
+		if len(f.problems) > 1 {
+			problem += fmt.Sprintf(" (+%d more)", len(f.problems)-1)
+		}
+		lines = append(lines, styles.Warning.Render(truncateTitle(problem, width)))
+	}
+
+	return styles.Body.
+		Padding(0, 1).
+		Height(m.composerRows()).
+		MaxHeight(m.composerRows()).
+		Render(strings.Join(lines, "\n"))
+}
+
+// ============= Submission Rules =============
+
+// submitRules are a subreddit's posting requirements, from its settings
+// and /api/v1/<sub>/post_requirements.
+type submitRules struct {
+	SubmissionType string   // "any", "self" or "link"
+	TitleMin       int      `json:"title_text_min_length"`
+	TitleMax       int      `json:"title_text_max_length"`
+	TitleRequired  []string `json:"title_required_strings"` // one must appear
+	TitleBanned    []string `json:"title_blacklisted_strings"`
+	TitleRegexes   []string `json:"title_regexes"`
+	BodyPolicy     string   `json:"body_restriction_policy"` // "required", "notAllowed" or "none"
+	BodyMin        int      `json:"body_text_min_length"`
+	BodyMax        int      `json:"body_text_max_length"`
+	BodyRequired   []string `json:"body_required_strings"`
+	BodyBanned     []string `json:"body_blacklisted_strings"`
+	LinkPolicy     string   `json:"link_restriction_policy"` // "whitelist", "blacklist" or "none"
+	DomainsAllowed []string `json:"domain_whitelist"`
+	DomainsBanned  []string `json:"domain_blacklist"`
+	FlairRequired  bool     `json:"is_flair_required"`
+}
+
+// summary lists the rules a post is most likely to trip over.
+func (r submitRules) summary() []string {
+	var parts []string
+	switch r.SubmissionType {
+	case "self":
+		parts = append(parts, "text posts only")
+	case "link":
+		parts = append(parts, "link posts only")
+	}
+	if r.TitleMin > 0 || (r.TitleMax > 0 && r.TitleMax < maxTitleLength) {
+		parts = append(parts, fmt.Sprintf("title %d-%d characters", r.TitleMin, r.titleMax()))
+	}
+	if len(r.TitleRequired) > 0 {
+		parts = append(parts, "title must include "+quoteList(r.TitleRequired, " or "))
+	}
+	switch r.BodyPolicy {
+	case "required":
+		parts = append(parts, "body text required")
+	case "notAllowed":
+		parts = append(parts, "no body text")
+	}
+	if r.LinkPolicy == "whitelist" && len(r.DomainsAllowed) > 0 {
+		parts = append(parts, "links to "+strings.Join(r.DomainsAllowed, ", ")+" only")
+	}
+	if r.FlairRequired {
+		parts = append(parts, "flair required")
+	}
+	return parts
+}
+
+func (r submitRules) titleMax() int {
+	if r.TitleMax > 0 && r.TitleMax < maxTitleLength {
+		return r.TitleMax
+	}
+	return maxTitleLength
+}
+
+func (f postForm) titleMax() int {
+	if f.rules == nil {
+		return maxTitleLength
+	}
+	return f.rules.titleMax()
+}
+
+func quoteList(items []string, sep string) string {
+	quoted := make([]string, len(items))
+	for i, s := range items {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	return strings.Join(quoted, sep)
+}
+
+// validate checks the post against the subreddit's rules, or only the
+// basics while they are not loaded, and returns what is wrong with it.
+func (f postForm) validate() []string {
+	var problems []string
+	p := f.newPost()
+	r := submitRules{}
+	if f.rules != nil && strings.EqualFold(p.subreddit, f.rulesFor) {
+		r = *f.rules
+	}
+
+	if p.subreddit == "" {
+		problems = append(problems, "Choose a subreddit")
+	}
+	switch {
+	case p.link && r.SubmissionType == "self":
+		problems = append(problems, "r/"+p.subreddit+" only allows text posts")
+	case !p.link && r.SubmissionType == "link":
+		problems = append(problems, "r/"+p.subreddit+" only allows link posts")
+	}
+
+	// Title
+	title := strings.ToLower(p.title)
+	n := utf8.RuneCountInString(p.title)
+	switch {
+	case n == 0:
+		problems = append(problems, "Title is required")
+	case n < r.TitleMin:
+		problems = append(problems, fmt.Sprintf("Title must be at least %d characters", r.TitleMin))
+	case n > r.titleMax():
+		problems = append(problems, fmt.Sprintf("Title must be at most %d characters", r.titleMax()))
+	}
+	if len(r.TitleRequired) > 0 && !containsAny(title, r.TitleRequired) {
+		problems = append(problems, "Title must include "+quoteList(r.TitleRequired, " or "))
+	}
+	for _, banned := range r.TitleBanned {
+		if banned != "" && strings.Contains(title, strings.ToLower(banned)) {
+			problems = append(problems, fmt.Sprintf("Title may not include %q", banned))
+		}
+	}
+	for _, pattern := range r.TitleRegexes {
+		// Patterns Go cannot compile are left for Reddit to check
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(p.title) {
+			problems = append(problems, "Title does not match the required format "+pattern)
+		}
+	}
+
+	// Body or link
+	if p.link {
+		problems = append(problems, r.checkLink(p.url)...)
+	} else {
+		body := strings.ToLower(p.text)
+		n := utf8.RuneCountInString(p.text)
+		switch {
+		case r.BodyPolicy == "required" && n == 0:
+			problems = append(problems, "r/"+p.subreddit+" requires body text")
+		case r.BodyPolicy == "notAllowed" && n > 0:
+			problems = append(problems, "r/"+p.subreddit+" does not allow body text")
+		case n > 0 && n < r.BodyMin:
+			problems = append(problems, fmt.Sprintf("Body must be at least %d characters", r.BodyMin))
+		case r.BodyMax > 0 && n > r.BodyMax:
+			problems = append(problems, fmt.Sprintf("Body must be at most %d characters", r.BodyMax))
+		}
+		if n > 0 && len(r.BodyRequired) > 0 && !containsAny(body, r.BodyRequired) {
+			problems = append(problems, "Body must include "+quoteList(r.BodyRequired, " or "))
+		}
+		for _, banned := range r.BodyBanned {
+			if banned != "" && strings.Contains(body, strings.ToLower(banned)) {
+				problems = append(problems, fmt.Sprintf("Body may not include %q", banned))
+			}
+		}
+	}
+
+	if r.FlairRequired && p.flairID == "" {
+		problems = append(problems, "r/"+p.subreddit+" requires a flair")
+	}
+	return problems
+}
+
+// checkLink checks a link post's URL against the domain rules.
+func (r submitRules) checkLink(link string) []string {
+	if link == "" {
+		return []string{"URL is required"}
+	}
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return []string{"URL must be an http:// or https:// link"}
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	onDomain := func(domains []string) bool {
+		for _, d := range domains {
+			d = strings.TrimPrefix(strings.ToLower(d), "www.")
+			if host == d || strings.HasSuffix(host, "."+d) {
+				return true
+			}
+		}
+		return false
+	}
+	if r.LinkPolicy == "whitelist" && len(r.DomainsAllowed) > 0 && !onDomain(r.DomainsAllowed) {
+		return []string{"Links must be to " + strings.Join(r.DomainsAllowed, ", ")}
+	}
+	if onDomain(r.DomainsBanned) {
+		return []string{"Links to " + host + " are not allowed"}
+	}
+	return nil
+}
+
+func containsAny(s string, subs []string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, strings.ToLower(sub)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ============= Submit API =============
+
+type newPost struct {
+	subreddit string
+	title     string
+	text      string // text posts
+	url       string // link posts
+	flairID   string
+	flairText string
+	link      bool
+	nsfw      bool
+	spoiler   bool
+}
+
+// SubmitRules fetches a subreddit's posting rules and the link flairs users
+// may pick. Only a missing or private subreddit is an error: flairs are
+// forbidden where they are disabled, and the requirements are optional.
+func (c *APIClient) SubmitRules(subreddit string) (submitRules, []linkFlair, error) {
+	var about struct {
+		Data struct {
+			SubmissionType string `json:"submission_type"`
+		} `json:"data"`
+	}
+	if err := c.getJSON("/r/"+url.PathEscape(subreddit)+"/about", &about); err != nil {
+		return submitRules{}, nil, err
+	}
+
+	var rules submitRules
+	c.getJSON("/api/v1/"+url.PathEscape(subreddit)+"/post_requirements", &rules)
+	rules.SubmissionType = about.Data.SubmissionType
+
+	var flairs []linkFlair
+	c.getJSON("/r/"+url.PathEscape(subreddit)+"/api/link_flair_v2", &flairs)
+	return rules, flairs, nil
+}
+
+// Submit sends a new post and returns its ID.
+func (c *APIClient) Submit(p newPost) (string, error) {
+	form := url.Values{
+		"sr":          {p.subreddit},
+		"title":       {p.title},
+		"kind":        {"self"},
+		"text":        {p.text},
+		"nsfw":        {fmt.Sprint(p.nsfw)},
+		"spoiler":     {fmt.Sprint(p.spoiler)},
+		"sendreplies": {"true"},
+		"resubmit":    {"true"},
+	}
+	if p.link {
+		form.Set("kind", "link")
+		form.Set("url", p.url)
+		form.Del("text")
+	}
+	if p.flairID != "" {
+		form.Set("flair_id", p.flairID)
+		form.Set("flair_text", p.flairText)
+	}
+	data, err := c.postForm("/api/submit", form)
+	if err != nil {
+		return "", err
+	}
+
+	var reply struct {
+		JSON struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		} `json:"json"`
+	}
+	if err := json.Unmarshal(data, &reply); err != nil {
+		return "", fmt.Errorf("failed to parse reply: %w", err)
+	}
+	if reply.JSON.Data.ID == "" {
+		return "", fmt.Errorf("post sent, but Reddit did not return it")
+	}
+	return strings.TrimPrefix(reply.JSON.Data.ID, "t3_"), nil
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// ============= Theme =============
+
+// Theme is the palette the TUI is drawn with. Theme files and the "colors"
+// config section use the same JSON keys; empty fields keep the base theme's value.
+type Theme struct {
+	Accent    lipgloss.Color `json:"accent"`     // header, titles, separators
+	Selection lipgloss.Color `json:"selection"`  // selected post
+	OnAccent  lipgloss.Color `json:"on_accent"`  // text drawn on accent and selection
+	Highlight lipgloss.Color `json:"highlight"`  // metadata, prompts, toasts
+	Success   lipgloss.Color `json:"success"`    // key hints, pinned marker
+	Text      lipgloss.Color `json:"text"`       // post and comment bodies
+	Surface   lipgloss.Color `json:"surface"`    // footer background
+	OnSurface lipgloss.Color `json:"on_surface"` // footer text
+	Info      lipgloss.Color `json:"info"`       // flair badge
+	OnInfo    lipgloss.Color `json:"on_info"`    // flair text
+	Error     lipgloss.Color `json:"error"`      // errors, NSFW badge
+}
+
+var builtinThemes = map[string]Theme{
+	"dark": {
+		Accent:    "#FF4500",
+		Selection: "#FF6B35",
+		OnAccent:  "#FFFFFF",
+		Highlight: "#FFD700",
+		Success:   "#90EE90",
+		Text:      "#CCCCCC",
+		Surface:   "#333333",
+		OnSurface: "#FFFFFF",
+		Info:      "#87CEEB",
+		OnInfo:    "#000000",
+		Error:     "#FF0000",
+	},
+	"light": {
+		Accent:    "#D93A00",
+		Selection: "#FF6B35",
+		OnAccent:  "#FFFFFF",
+		Highlight: "#8A5A00",
+		Success:   "#2E7D32",
+		Text:      "#333333",
+		Surface:   "#DDDDDD",
+		OnSurface: "#000000",
+		Info:      "#005F87",
+		OnInfo:    "#FFFFFF",
+		Error:     "#C00000",
+	},
+	"high-contrast": {
+		Accent:    "#FFFF00",
+		Selection: "#FFFFFF",
+		OnAccent:  "#000000",
+		Highlight: "#FFFF00",
+		Success:   "#00FF00",
+		Text:      "#FFFFFF",
+		Surface:   "#FFFFFF",
+		OnSurface: "#000000",
+		Info:      "#00FFFF",
+		OnInfo:    "#000000",
+		Error:     "#FF0000",
+	},
+	"solarized": {
+		Accent:    "#CB4B16",
+		Selection: "#268BD2",
+		OnAccent:  "#FDF6E3",
+		Highlight: "#B58900",
+		Success:   "#859900",
+		Text:      "#93A1A1",
+		Surface:   "#073642",
+		OnSurface: "#93A1A1",
+		Info:      "#2AA198",
+		OnInfo:    "#002B36",
+		Error:     "#DC322F",
+	},
+}
+
+// merge returns t with every non-empty field of o applied on top.
+func (t Theme) merge(o Theme) Theme {
+	pick := func(base, over lipgloss.Color) lipgloss.Color {
+		if over != "" {
+			return over
+		}
+		return base
+	}
+	return Theme{
+		Accent:    pick(t.Accent, o.Accent),
+		Selection: pick(t.Selection, o.Selection),
+		OnAccent:  pick(t.OnAccent, o.OnAccent),
+		Highlight: pick(t.Highlight, o.Highlight),
+		Success:   pick(t.Success, o.Success),
+		Text:      pick(t.Text, o.Text),
+		Surface:   pick(t.Surface, o.Surface),
+		OnSurface: pick(t.OnSurface, o.OnSurface),
+		Info:      pick(t.Info, o.Info),
+		OnInfo:    pick(t.OnInfo, o.OnInfo),
+		Error:     pick(t.Error, o.Error),
+	}
+}
+
+// Styles are the lipgloss styles derived from the active theme.
+type Styles struct {
+	Header    lipgloss.Style
+	Footer    lipgloss.Style
+	Focused   lipgloss.Style
+	Error     lipgloss.Style
+	Prompt    lipgloss.Style // info bar prompts and toasts
+	Hint      lipgloss.Style // info bar key hints
+	Meta      lipgloss.Style // author/score lines
+	Body      lipgloss.Style // detail and comment panels
+	Separator lipgloss.Style
+	Warning   lipgloss.Style
+	Badge     lipgloss.Style
+}
+
+var (
+	theme  = builtinThemes["dark"]
+	styles = newStyles(theme)
+
+	// noColor is set from NO_COLOR; emphasis then relies on bold and reverse video
+	noColor bool
+)
+
+func newStyles(t Theme) Styles {
+	s := Styles{
+		Header: lipgloss.NewStyle().
+			Background(t.Accent).
+			Foreground(t.OnAccent).
+			Bold(true).
+			Padding(0, 1),
+		Footer: lipgloss.NewStyle().
+			Background(t.Surface).
+			Foreground(t.OnSurface).
+			Padding(0, 1),
+		Focused: lipgloss.NewStyle().
+			Foreground(t.Accent).
+			Bold(true),
+		Error: lipgloss.NewStyle().
+			Foreground(t.Error).
+			Bold(true),
+		Prompt:    lipgloss.NewStyle().Foreground(t.Highlight).Padding(0, 1),
+		Hint:      lipgloss.NewStyle().Foreground(t.Success).Padding(0, 1),
+		Meta:      lipgloss.NewStyle().Foreground(t.Highlight),
+		Body:      lipgloss.NewStyle().Foreground(t.Text),
+		Separator: lipgloss.NewStyle().Foreground(t.Accent),
+		Warning:   lipgloss.NewStyle().Foreground(t.Accent),
+		Badge:     lipgloss.NewStyle().Padding(0, 1).Bold(true),
+	}
+	if noColor {
+		s.Header = s.Header.Reverse(true)
+		s.Footer = s.Footer.Reverse(true)
+	}
+	return s
+}
+
+// newListDelegate returns the post list delegate drawn in the theme's colors.
+func newListDelegate() list.DefaultDelegate {
+	d := list.NewDefaultDelegate()
+	d.Styles.SelectedTitle = d.Styles.SelectedTitle.
+		Foreground(theme.Accent).
+		BorderLeftForeground(theme.Accent)
+	d.Styles.SelectedDesc = d.Styles.SelectedDesc.
+		Foreground(theme.Selection).
+		BorderLeftForeground(theme.Accent)
+	return d
+}
+
+// loadTheme resolves the configured theme: a built-in preset or a theme file,
+// with the "colors" overrides from config.json applied last. "auto" picks
+// dark or light from the terminal's background color.
+func loadTheme() (Theme, error) {
+	if os.Getenv("NO_COLOR") != "" {
+		noColor = true
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+
+	name := strings.ToLower(appConfig.TUI.Theme)
+	if name == "" || name == "auto" {
+		name = "light"
+		if lipgloss.HasDarkBackground() {
+			name = "dark"
+		}
+	}
+
+	t, ok := builtinThemes[name]
+	if !ok {
+		// Not a preset: look for <name>.json in the themes directory
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return builtinThemes["dark"], fmt.Errorf("unknown theme %q", name)
+		}
+		t, err = readThemeFile(filepath.Join(dir, "redditview", "themes", name+".json"), builtinThemes["dark"])
+		if err != nil {
+			return builtinThemes["dark"], fmt.Errorf("unknown theme %q: %w", name, err)
+		}
+	}
+
+	if appConfig.TUI.ThemeFile != "" {
+		var err error
+		if t, err = readThemeFile(appConfig.TUI.ThemeFile, t); err != nil {
+			return t, err
+		}
+	}
+
+	return t.merge(appConfig.TUI.Colors), nil
+}
+
+// readThemeFile loads a JSON theme, filling unset colors from base.
+func readThemeFile(path string, base Theme) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return base, err
+	}
+	var t Theme
+	if err := json.Unmarshal(data, &t); err != nil {
+		return base, fmt.Errorf("failed to parse theme %s: %w", path, err)
+	}
+	return base.merge(t), nil
+}
+
+func applyTheme(t Theme) {
+	theme = t
+	styles = newStyles(t)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// ============= Time Formatting =============
+
+const absoluteTimeLayout = "2006-01-02 15:04"
+
+// Clock is the source of the current time for relative ages.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// clock is swapped for a fixed clock when ages must be reproducible.
+var clock Clock = systemClock{}
+
+// formatTimestamp renders a Unix timestamp from the API either as a relative
+// age or, when absolute is set, as local wall-clock time.
+func formatTimestamp(created float64, absolute bool) string {
+	if created <= 0 {
+		return ""
+	}
+	t := time.Unix(int64(created), 0)
+	if absolute {
+		return t.Local().Format(absoluteTimeLayout)
+	}
+	return formatRelativeTime(t, clock.Now())
+}
+
+// formatRelativeTime renders the time elapsed between t and now as a compact
+// age such as "3h ago". Times in the future count as "just now".
+func formatRelativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo ago", int(d.Hours()/(24*30)))
+	}
+	return fmt.Sprintf("%dy ago", int(d.Hours()/(24*365)))
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// fixedClock always reports the same time.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+// useClock makes clock report now for one test.
+func useClock(t *testing.T, now time.Time) {
+	saved := clock
+	t.Cleanup(func() { clock = saved })
+	clock = fixedClock(now)
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	useClock(t, now)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name string
+		age  time.Duration
+		want string
+	}{
+		{"now", 0, "just now"},
+		{"59 seconds", 59 * time.Second, "just now"},
+		{"1 minute", time.Minute, "1m ago"},
+		{"59 minutes", 59*time.Minute + 59*time.Second, "59m ago"},
+		{"1 hour", time.Hour, "1h ago"},
+		{"23 hours", 23*time.Hour + 59*time.Minute, "23h ago"},
+		{"1 day", day, "1d ago"},
+		{"29 days", 29*day + 23*time.Hour, "29d ago"},
+		{"30 days", 30 * day, "1mo ago"},
+		{"364 days", 364 * day, "12mo ago"},
+		{"365 days", 365 * day, "1y ago"},
+		{"3 years", 3 * 365 * day, "3y ago"},
+		{"a minute ahead", -time.Minute, "just now"},
+		{"a day ahead", -day, "just now"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := float64(now.Add(-tt.age).Unix())
+			if got := formatTimestamp(created, false); got != tt.want {
+				t.Errorf("formatTimestamp(now - %v) = %q, want %q", tt.age, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatTimestampAbsoluteAndMissing(t *testing.T) {
+	useClock(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	created := time.Date(2024, 4, 30, 9, 5, 0, 0, time.Local)
+	if got, want := formatTimestamp(float64(created.Unix()), true), "2024-04-30 09:05"; got != want {
+		t.Errorf("absolute = %q, want %q", got, want)
+	}
+	if got := formatTimestamp(0, false); got != "" {
+		t.Errorf("no timestamp = %q, want empty", got)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ============= Voting =============
+
+// Votes show at once: the score and marker change before the request is
+// sent, and are put back if Reddit refuses it.
+
+// voteScore renders a score with the user's vote: ▲ upvoted, ▼ downvoted.
+func voteScore(score int, vote RedditVote) string {
+	switch vote {
+	case voteUp:
+		return "▲ " + formatNum(score)
+	case voteDown:
+		return "▼ " + formatNum(score)
+	}
+	return "⬆ " + formatNum(score)
+}
+
+// Vote casts dir on a post ("t3_...") or comment ("t1_...").
+func (c *APIClient) Vote(fullname string, dir RedditVote) error {
+	_, err := c.postForm("/api/vote", url.Values{
+		"id":  {fullname},
+		"dir": {fmt.Sprint(int(dir))},
+	})
+	return err
+}
+
+type voteDoneMsg struct {
+	fullname string
+	prev     RedditVote
+	error    error
+}
+
+// target is what vote and reply keys act on: the focused comment when
+// comments are being read, otherwise just the selected post.
+func (m Model) target() (post *RedditPostData, comment *Comment) {
+	if len(m.filteredPosts) == 0 || m.list.Index() >= len(m.filteredPosts) {
+		return nil, nil
+	}
+	post = &m.filteredPosts[m.list.Index()]
+	inComments := m.showDetails && m.showComments
+	if m.showDetails && m.reading {
+		_, commentsAt := m.readerLines()
+		inComments = m.reader.YOffset >= commentsAt
+	}
+	if inComments {
+		comment = m.focusedComment()
+	}
+	return post, comment
+}
+
+// vote casts dir on the target. Voting the same way twice clears the
+// vote, as on the site.
+func (m *Model) vote(dir RedditVote) tea.Cmd {
+	if !m.client.Authenticated() {
+		return m.showToast("⚠ Log in to vote (redditview login)")
+	}
+	post, comment := m.target()
+	if post == nil {
+		return nil
+	}
+	fullname, prev, label := "t3_"+post.ID, post.Likes, "post"
+	if comment != nil {
+		fullname, prev, label = "t1_"+comment.ID, comment.Likes, "comment by u/"+comment.Author
+	}
+	if dir == prev {
+		dir = voteNone
+	}
+	m.setVote(fullname, dir)
+
+	client := m.client
+	send := func() tea.Msg {
+		return voteDoneMsg{fullname, prev, client.Vote(fullname, dir)}
+	}
+	switch dir {
+	case voteUp:
+		return tea.Batch(m.showToast("▲ Upvoted "+label), send)
+	case voteDown:
+		return tea.Batch(m.showToast("▼ Downvoted "+label), send)
+	}
+	return tea.Batch(m.showToast("Vote cleared on "+label), send)
+}
+
+// setVote records the user's vote on a post or comment, adjusting its score
+// by the difference from the previous vote.
+func (m *Model) setVote(fullname string, dir RedditVote) {
+	kind, id := fullname[:3], fullname[3:]
+	if kind == "t1_" {
+		for _, c := range m.flatComments() {
+			if c.ID == id {
+				c.Score += int(dir - c.Likes)
+				c.Likes = dir
+			}
+		}
+		m.relayout()
+		return
+	}
+
+	for i := range m.posts {
+		if m.posts[i].ID == id {
+			m.posts[i].Score += int(dir - m.posts[i].Likes)
+			m.posts[i].Likes = dir
+		}
+	}
+	for i := range m.filteredPosts {
+		if m.filteredPosts[i].ID == id {
+			m.filteredPosts[i].Score += int(dir - m.filteredPosts[i].Likes)
+			m.filteredPosts[i].Likes = dir
+			m.list.SetItem(i, m.postItem(m.filteredPosts[i]))
+		}
+	}
+	m.relayout()
+}
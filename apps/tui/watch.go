@@ -0,0 +1,401 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ============= Watch Rules =============
+
+// Watch rules raise an alert when the background refresher finds a post
+// that matches: a toast, the terminal bell and, when notify-send is
+// installed and desktop notifications are on, a desktop notification.
+// Besides the listing on screen, the refresher fetches the newest posts of
+// every subreddit a rule names. Posts already there when a listing or the
+// watched subreddits are first loaded do not alert; each post alerts once
+// per rule. Alerts are appended to a log file and listed by the alerts key.
+
+type watchRule struct {
+	Name       string   `json:"name"`
+	Subreddits []string `json:"subreddits"` // empty: any subreddit
+	Keywords   []string `json:"keywords"`   // any one, in the title or text
+	Regex      string   `json:"regex"`      // matched against the title and text
+	MinScore   int      `json:"min_score"`
+
+	re *regexp.Regexp
+}
+
+// maxAlerts is how many past alerts are kept and read back from the log.
+const maxAlerts = 200
+
+// watchFetchLimit is how many of the watched subreddits' newest posts each
+// refresh checks.
+const watchFetchLimit = 50
+
+// watchRules are the configured rules that can work, ready to match.
+var watchRules []watchRule
+
+// compileWatchRules builds watchRules from the config, dropping the rules
+// that cannot work and blank keywords, which would match every post. It
+// returns what was wrong. The config itself is left as it is.
+func compileWatchRules() []string {
+	var problems []string
+	var rules []watchRule
+	for i, configured := range appConfig.Watch.Rules {
+		rule := watchRule{Name: configured.Name, Regex: configured.Regex, MinScore: configured.MinScore}
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		if rule.Regex != "" {
+			re, err := regexp.Compile(rule.Regex)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("watch rule %q: bad regex: %v", rule.Name, err))
+				continue
+			}
+			rule.re = re
+		}
+		for _, keyword := range configured.Keywords {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				rule.Keywords = append(rule.Keywords, keyword)
+			}
+		}
+		if len(rule.Keywords) == 0 && rule.re == nil && rule.MinScore <= 0 {
+			problems = append(problems, fmt.Sprintf("watch rule %q has no keywords, regex or min_score", rule.Name))
+			continue
+		}
+		if len(rule.Keywords) < len(configured.Keywords) {
+			problems = append(problems, fmt.Sprintf("watch rule %q: blank keywords ignored", rule.Name))
+		}
+		for _, sub := range configured.Subreddits {
+			if sub = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(sub), "/"), "r/"); sub != "" {
+				rule.Subreddits = append(rule.Subreddits, sub)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	watchRules = rules
+	if len(rules) > 0 && appConfig.TUI.AutoRefreshSeconds <= 0 {
+		problems = append(problems, "watch rules only run with auto_refresh_seconds set")
+	}
+	return problems
+}
+
+// matches reports whether post meets every condition the rule sets.
+func (r watchRule) matches(post RedditPostData) bool {
+	if len(r.Subreddits) > 0 {
+		found := false
+		for _, sub := range r.Subreddits {
+			if strings.EqualFold(sub, post.SubName) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if post.Score < r.MinScore {
+		return false
+	}
+	text := post.Title + "\n" + post.SelfText
+	if len(r.Keywords) > 0 && !containsAny(strings.ToLower(text), r.Keywords) {
+		return false
+	}
+	if r.re != nil && !r.re.MatchString(text) {
+		return false
+	}
+	return true
+}
+
+// watchedSubreddits joins the subreddits named by rules into one
+// multireddit, or returns "" when no rule names any.
+func watchedSubreddits() string {
+	var subs []string
+	seen := make(map[string]bool)
+	for _, rule := range watchRules {
+		for _, sub := range rule.Subreddits {
+			if !seen[strings.ToLower(sub)] {
+				seen[strings.ToLower(sub)] = true
+				subs = append(subs, sub)
+			}
+		}
+	}
+	return strings.Join(subs, "+")
+}
+
+type watchAlert struct {
+	Time      time.Time `json:"time"`
+	Rule      string    `json:"rule"`
+	Subreddit string    `json:"subreddit"`
+	PostID    string    `json:"post_id"`
+	Title     string    `json:"title"`
+	Score     int       `json:"score"`
+}
+
+func (a watchAlert) key() string {
+	return a.Rule + "/" + a.PostID
+}
+
+type watchFetchedMsg struct {
+	posts []RedditPostData
+	error error
+}
+
+// fetchWatched loads the newest posts of the watched subreddits.
+func (m Model) fetchWatched() tea.Cmd {
+	subs := watchedSubreddits()
+	if subs == "" {
+		return nil
+	}
+	client := m.client
+	return func() tea.Msg {
+		posts, err := client.FetchPosts(subs, "new", watchFetchLimit)
+		return watchFetchedMsg{posts, err}
+	}
+}
+
+// checkWatch runs the rules over posts. Matches not seen before are
+// recorded, and when alert is set they are raised; otherwise they are only
+// remembered so they will not alert later.
+func (m *Model) checkWatch(posts []RedditPostData, alert bool) tea.Cmd {
+	if len(watchRules) == 0 {
+		return nil
+	}
+	if m.alerted == nil {
+		m.alerted = make(map[string]bool)
+	}
+	var raised []watchAlert
+	for _, post := range posts {
+		for _, rule := range watchRules {
+			if !rule.matches(post) {
+				continue
+			}
+			a := watchAlert{clock.Now(), rule.Name, post.SubName, post.ID, post.Title, post.Score}
+			if m.alerted[a.key()] {
+				continue
+			}
+			m.alerted[a.key()] = true
+			if alert {
+				raised = append(raised, a)
+			}
+		}
+	}
+	if len(raised) == 0 {
+		return nil
+	}
+
+	m.alerts = append(m.alerts, raised...)
+	if len(m.alerts) > maxAlerts {
+		m.alerts = m.alerts[len(m.alerts)-maxAlerts:]
+	}
+	m.unseenAlerts += len(raised)
+
+	first := raised[0]
+	text := fmt.Sprintf("🔔 %s: %s", first.Rule, first.Title)
+	if len(raised) > 1 {
+		text = fmt.Sprintf("🔔 %d watch alerts (%s: %s, ...)", len(raised), first.Rule, first.Title)
+	}
+	cmds := []tea.Cmd{m.showToast(text), logAlerts(raised)}
+	if !appConfig.Watch.Silent {
+		cmds = append(cmds, ringBell)
+	}
+	if appConfig.Watch.Desktop {
+		cmds = append(cmds, notifyDesktop(raised))
+	}
+	return tea.Batch(cmds...)
+}
+
+// ringBell sounds the terminal bell. Like the clipboard's OSC 52 sequence,
+// it is written to stderr so it does not interleave with the renderer's
+// frames.
+func ringBell() tea.Msg {
+	os.Stderr.WriteString("\a")
+	return nil
+}
+
+// notifyDesktop shows a desktop notification through notify-send, when it
+// is installed. Failures are ignored: the alert is already in the app.
+func notifyDesktop(alerts []watchAlert) tea.Cmd {
+	return func() tea.Msg {
+		path, err := exec.LookPath("notify-send")
+		if err != nil {
+			return nil
+		}
+		for i, a := range alerts {
+			if i == 3 {
+				exec.Command(path, "-a", "redditview", fmt.Sprintf("%d more watch alerts", len(alerts)-i)).Run()
+				break
+			}
+			summary := fmt.Sprintf("%s · r/%s", a.Rule, a.Subreddit)
+			exec.Command(path, "-a", "redditview", summary, a.Title).Run()
+		}
+		return nil
+	}
+}
+
+// ============= Alert Log =============
+
+// alertLogPath is watch.log_file from the config, with a leading "~"
+// expanded, or alerts.log in the redditview config directory.
+func alertLogPath() string {
+	path := appConfig.Watch.LogFile
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, path[1:])
+	}
+	if path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "redditview-alerts.log"
+	}
+	return filepath.Join(dir, "redditview", "alerts.log")
+}
+
+// loadAlerts reads the most recent alerts back from the log, one JSON
+// object per line. Lines that do not parse are skipped.
+func loadAlerts() []watchAlert {
+	f, err := os.Open(alertLogPath())
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var alerts []watchAlert
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var a watchAlert
+		if json.Unmarshal(scanner.Bytes(), &a) == nil && a.PostID != "" {
+			alerts = append(alerts, a)
+		}
+	}
+	if len(alerts) > maxAlerts {
+		alerts = alerts[len(alerts)-maxAlerts:]
+	}
+	return alerts
+}
+
+type alertLogMsg struct {
+	error error
+}
+
+// logAlerts appends alerts to the log.
+func logAlerts(alerts []watchAlert) tea.Cmd {
+	return func() tea.Msg {
+		path := alertLogPath()
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return alertLogMsg{err}
+		}
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return alertLogMsg{err}
+		}
+		defer f.Close()
+		enc := json.NewEncoder(f)
+		enc.SetEscapeHTML(false)
+		for _, a := range alerts {
+			if err := enc.Encode(a); err != nil {
+				return alertLogMsg{err}
+			}
+		}
+		return alertLogMsg{nil}
+	}
+}
+
+// ============= Alerts View =============
+
+// handleAlertsKey drives the alert log: moving through it and opening the
+// post an alert was raised for.
+func (m Model) handleAlertsKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	k := m.keys
+	last := len(m.alerts) - 1
+	page := max(1, m.composerRows()-1)
+	switch {
+	case key.Matches(msg, k.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, k.Alerts, k.Back):
+		m.showAlerts = false
+	case key.Matches(msg, k.Up):
+		m.alertCursor = max(0, m.alertCursor-1)
+	case key.Matches(msg, k.Down):
+		m.alertCursor = max(0, min(last, m.alertCursor+1))
+	case key.Matches(msg, k.PageUp, k.HalfPageUp):
+		m.alertCursor = max(0, m.alertCursor-page)
+	case key.Matches(msg, k.PageDown, k.HalfPageDown):
+		m.alertCursor = max(0, min(last, m.alertCursor+page))
+	case key.Matches(msg, k.Top):
+		m.alertCursor = 0
+	case key.Matches(msg, k.Bottom):
+		m.alertCursor = max(0, last)
+	case key.Matches(msg, k.Open):
+		if m.alertCursor > last {
+			return m, nil
+		}
+		// Newest first: the cursor counts back from the end
+		a := m.alerts[last-m.alertCursor]
+		m.showAlerts = false
+		ref := postRef{subreddit: a.Subreddit, id: a.PostID}
+		return m, tea.Batch(m.showToast("🔗 Opening post "+a.PostID+"..."), m.fetchPost(ref))
+	}
+	return m, nil
+}
+
+// openAlerts shows the alert log, newest first.
+func (m *Model) openAlerts() {
+	m.showAlerts = true
+	m.alertCursor = 0
+	m.unseenAlerts = 0
+}
+
+func (m Model) alertsTitle() string {
+	names := make([]string, len(watchRules))
+	for i, rule := range watchRules {
+		names[i] = rule.Name
+	}
+	title := fmt.Sprintf("🔔 Watch alerts (%d)", len(m.alerts))
+	if len(names) > 0 {
+		title += "  rules: " + strings.Join(names, ", ")
+	} else {
+		title += "  no watch rules configured"
+	}
+	return title
+}
+
+func (m Model) renderAlerts() string {
+	width := max(20, m.windowWidth-2)
+	rows := m.composerRows()
+
+	var lines []string
+	if len(m.alerts) == 0 {
+		lines = append(lines, styles.Meta.Render("No alerts yet"))
+	}
+	for i := len(m.alerts) - 1; i >= 0; i-- {
+		a := m.alerts[i]
+		when := a.Time.Local().Format("Jan 2 15:04")
+		line := fmt.Sprintf("%s  [%s] r/%s  %s  %s", when, a.Rule, a.Subreddit, a.Title, voteScore(a.Score, voteNone))
+		line = truncateTitle(line, width-2)
+		if len(m.alerts)-1-i == m.alertCursor {
+			line = styles.Focused.Render("▸ " + line)
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+
+	top := max(0, m.alertCursor-rows+1)
+	lines = lines[min(top, len(lines)):]
+	return styles.Body.
+		Padding(0, 1).
+		Height(rows).
+		MaxHeight(rows).
+		Render(strings.Join(lines, "\n"))
+}